@@ -2,52 +2,141 @@ package gsql
 
 // Aggregate 聚合函数
 type Aggregate struct {
-	fn    string
-	arg   string
-	alias string
+	fn       string
+	arg      Expression
+	alias    string
+	distinct bool
 }
 
 func (a Aggregate) selectable() {}
+func (a Aggregate) expr()       {}
 
 func (a Aggregate) As(alias string) Aggregate {
 	return Aggregate{
-		fn:    a.fn,
-		arg:   a.arg,
-		alias: alias,
+		fn:       a.fn,
+		arg:      a.arg,
+		alias:    alias,
+		distinct: a.distinct,
 	}
 }
 
-func Avg(col string) Aggregate {
+func Avg(col any) Aggregate {
 	return Aggregate{
 		fn:  "AVG",
-		arg: col,
+		arg: aggregateArg(col),
 	}
 }
 
-func Sum(col string) Aggregate {
+func Sum(col any) Aggregate {
 	return Aggregate{
 		fn:  "SUM",
-		arg: col,
+		arg: aggregateArg(col),
 	}
 }
 
-func Count(col string) Aggregate {
+func Count(col any) Aggregate {
 	return Aggregate{
 		fn:  "COUNT",
-		arg: col,
+		arg: aggregateArg(col),
 	}
 }
 
-func Max(col string) Aggregate {
+// AvgDistinct AVG(DISTINCT col)
+func AvgDistinct(col any) Aggregate {
+	return Aggregate{
+		fn:       "AVG",
+		arg:      aggregateArg(col),
+		distinct: true,
+	}
+}
+
+// SumDistinct SUM(DISTINCT col)
+func SumDistinct(col any) Aggregate {
+	return Aggregate{
+		fn:       "SUM",
+		arg:      aggregateArg(col),
+		distinct: true,
+	}
+}
+
+// CountDistinct COUNT(DISTINCT col)
+func CountDistinct(col any) Aggregate {
+	return Aggregate{
+		fn:       "COUNT",
+		arg:      aggregateArg(col),
+		distinct: true,
+	}
+}
+
+func Max(col any) Aggregate {
 	return Aggregate{
 		fn:  "MAX",
-		arg: col,
+		arg: aggregateArg(col),
 	}
 }
 
-func Min(col string) Aggregate {
+func Min(col any) Aggregate {
 	return Aggregate{
 		fn:  "MIN",
-		arg: col,
+		arg: aggregateArg(col),
+	}
+}
+
+func (a Aggregate) Eq(arg any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    opEQ,
+		right: valueOf(arg),
+	}
+}
+
+func (a Aggregate) Ne(arg any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    opNE,
+		right: valueOf(arg),
+	}
+}
+
+func (a Aggregate) Lt(arg any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    opLT,
+		right: valueOf(arg),
+	}
+}
+
+func (a Aggregate) Le(arg any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    opLE,
+		right: valueOf(arg),
+	}
+}
+
+func (a Aggregate) Gt(arg any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    opGT,
+		right: valueOf(arg),
+	}
+}
+
+func (a Aggregate) Ge(arg any) Predicate {
+	return Predicate{
+		left:  a,
+		op:    opGE,
+		right: valueOf(arg),
+	}
+}
+
+// aggregateArg 字符串是字段名，其它的表达式直接使用，
+// 例如 Sum(C("Price").Multi(C("Qty")))、Count(t1.C("Id"))
+func aggregateArg(col any) Expression {
+	switch c := col.(type) {
+	case string:
+		return C(c)
+	default:
+		return valueOf(c)
 	}
 }
@@ -0,0 +1,107 @@
+package gsql
+
+import (
+	"context"
+	"errors"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+)
+
+type batchConfig struct {
+	key   string
+	after any
+}
+
+type BatchOption func(cfg *batchConfig)
+
+// BatchKey 指定分批使用的主键字段，默认是 Id。
+// 这个字段的值必须是唯一并且可以比较大小的
+func BatchKey(field string) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.key = field
+	}
+}
+
+// BatchAfter 从 key 之后开始处理，用于从上一次中断的地方继续
+func BatchAfter(key any) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.after = key
+	}
+}
+
+// Batches 按照主键分批处理数据，每一批最多 size 条：
+//
+//	WHERE <原本的条件> AND pk > last ORDER BY pk LIMIT size
+//
+// 原本的 ORDER BY、LIMIT 和 OFFSET 会被忽略。
+// fn 返回 ErrStop 的时候提前结束，返回其它错误的时候 Batches 返回这个错误。
+// 每一批最后一条数据的主键就是下一次可以传给 BatchAfter 的检查点
+func (s *Selector[T]) Batches(ctx context.Context, size int, fn func([]*T) error, opts ...BatchOption) error {
+	if size <= 0 {
+		return errs.NewErrInvalidBatchSize(size)
+	}
+	cfg := &batchConfig{
+		key: "Id",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	fd, ok := s.model.FieldMap[cfg.key]
+	if !ok {
+		return errs.NewErrUnknownField(cfg.key)
+	}
+
+	// 按照表来限定主键，JOIN 的时候使用第一个有这个字段的表
+	key := C(cfg.key)
+	switch t := s.table.(type) {
+	case Table:
+		key = t.C(cfg.key)
+	case Join:
+		key = t.C(cfg.key)
+	case Subquery:
+		key = t.C(cfg.key)
+	}
+
+	last := cfg.after
+	for {
+		bs := s.derive()
+		// 下一批的起点是从结果里面读出来的，所以结果里面一定要有主键
+		bs.columns = withColumns(s.columns, key)
+		bs.where = make([]Predicate, 0, len(s.where)+1)
+		bs.where = append(bs.where, s.where...)
+		if last != nil {
+			bs.where = append(bs.where, key.Gt(last))
+		}
+		bs.orderBy = []OrderBy{Asc(key)}
+		bs.limit = size
+		bs.offset = 0
+
+		res := getMulti[T](ctx, s.session, s.core, &QueryContext{
+			Type:    TypeSelect,
+			Builder: bs,
+			Model:   s.model,
+		})
+		if errors.Is(res.Err, ErrNoRows) {
+			return nil
+		}
+		if res.Err != nil {
+			return res.Err
+		}
+		batch := res.Result.([]*T)
+
+		if err := fn(batch); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+		if len(batch) < size {
+			return nil
+		}
+
+		var err error
+		last, err = s.creator(s.model, batch[len(batch)-1]).Field(fd.GoName)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package gsql
+
+import (
+	"context"
+	"errors"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSelector_Batches(t *testing.T) {
+	db := memoryDB(t, WithDialect(DialectSQLite))
+	ctx := context.Background()
+	require.NoError(t, RawQuery[BatchItem](db, "CREATE TABLE IF NOT EXISTS `batch_item`("+
+		"`id` INTEGER PRIMARY KEY, `kind` TEXT)").Exec(ctx).Err())
+	require.NoError(t, RawQuery[BatchItem](db, "DELETE FROM `batch_item`").Exec(ctx).Err())
+	items := make([]*BatchItem, 0, 8)
+	for i := int64(1); i <= 8; i++ {
+		kind := "odd"
+		if i%2 == 0 {
+			kind = "even"
+		}
+		items = append(items, &BatchItem{Id: i, Kind: kind})
+	}
+	require.NoError(t, NewInserter[BatchItem](db).Values(items...).Exec(ctx).Err())
+
+	ids := func(batch []*BatchItem) []int64 {
+		res := make([]int64, 0, len(batch))
+		for _, item := range batch {
+			res = append(res, item.Id)
+		}
+		return res
+	}
+
+	testCases := []struct {
+		name string
+		s    *Selector[BatchItem]
+		size int
+		opts []BatchOption
+		// stopAt 处理完这一批之后返回的错误
+		stopAt  int
+		stopErr error
+
+		wantBatches [][]int64
+		wantErr     error
+	}{
+		{
+			name:        "all",
+			s:           NewSelector[BatchItem](db).OrderBy(Desc(C("Id"))).Limit(1),
+			size:        3,
+			wantBatches: [][]int64{{1, 2, 3}, {4, 5, 6}, {7, 8}},
+		},
+		{
+			name:        "exact size",
+			s:           NewSelector[BatchItem](db),
+			size:        4,
+			wantBatches: [][]int64{{1, 2, 3, 4}, {5, 6, 7, 8}},
+		},
+		{
+			name:        "where",
+			s:           NewSelector[BatchItem](db).Where(C("Kind").Eq("even")),
+			size:        3,
+			wantBatches: [][]int64{{2, 4, 6}, {8}},
+		},
+		{
+			name:        "alias",
+			s:           NewSelector[BatchItem](db).From(TableOf(&BatchItem{}).As("b")),
+			size:        5,
+			wantBatches: [][]int64{{1, 2, 3, 4, 5}, {6, 7, 8}},
+		},
+		{
+			// 没有选择主键的时候也要带上主键，不然每一批都是从头开始
+			name:        "columns without key",
+			s:           NewSelector[BatchItem](db).Select(C("Kind")),
+			size:        3,
+			wantBatches: [][]int64{{1, 2, 3}, {4, 5, 6}, {7, 8}},
+		},
+		{
+			name: "join",
+			s: func() *Selector[BatchItem] {
+				b1 := TableOf(&BatchItem{}).As("b1")
+				b2 := TableOf(&BatchItem{}).As("b2")
+				return NewSelector[BatchItem](db).
+					From(b1.Join(b2).On(b1.C("Id").Eq(b2.C("Id")))).
+					Where(b2.C("Kind").Eq("odd"))
+			}(),
+			size:        3,
+			wantBatches: [][]int64{{1, 3, 5}, {7}},
+		},
+		{
+			name:        "resume",
+			s:           NewSelector[BatchItem](db),
+			size:        3,
+			opts:        []BatchOption{BatchAfter(int64(5))},
+			wantBatches: [][]int64{{6, 7, 8}},
+		},
+		{
+			name:        "stop",
+			s:           NewSelector[BatchItem](db),
+			size:        3,
+			stopAt:      1,
+			stopErr:     ErrStop,
+			wantBatches: [][]int64{{1, 2, 3}},
+		},
+		{
+			name:        "callback error",
+			s:           NewSelector[BatchItem](db),
+			size:        3,
+			stopAt:      2,
+			stopErr:     errors.New("callback error"),
+			wantBatches: [][]int64{{1, 2, 3}, {4, 5, 6}},
+			wantErr:     errors.New("callback error"),
+		},
+		{
+			name:    "no rows",
+			s:       NewSelector[BatchItem](db).Where(C("Kind").Eq("none")),
+			size:    3,
+			wantErr: nil,
+		},
+		{
+			name:    "invalid size",
+			s:       NewSelector[BatchItem](db),
+			size:    0,
+			wantErr: errs.NewErrInvalidBatchSize(0),
+		},
+		{
+			name:    "invalid key",
+			s:       NewSelector[BatchItem](db),
+			size:    3,
+			opts:    []BatchOption{BatchKey("Invalid")},
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var batches [][]int64
+			err := tc.s.Batches(ctx, tc.size, func(batch []*BatchItem) error {
+				batches = append(batches, ids(batch))
+				if len(batches) == tc.stopAt {
+					return tc.stopErr
+				}
+				return nil
+			}, tc.opts...)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantBatches, batches)
+		})
+	}
+}
+
+type BatchItem struct {
+	Id   int64
+	Kind string
+}
@@ -10,6 +10,21 @@ type builder struct {
 	sb     strings.Builder
 	args   []any
 	quoter byte
+
+	// aliases 是 SELECT 里面声明过的别名，HAVING 可以引用这些别名
+	aliases map[string]struct{}
+	// outerCTEs 是外层查询声明的公共表表达式，ctes 还包括当前查询自己声明的
+	outerCTEs map[string]commonTableExpr
+	ctes      map[string]commonTableExpr
+}
+
+// cteInheritor 嵌套的查询继承外层声明的公共表表达式，这样才能按照元数据找到列名
+type cteInheritor interface {
+	inheritCTEs(ctes map[string]commonTableExpr)
+}
+
+func (b *builder) inheritCTEs(ctes map[string]commonTableExpr) {
+	b.outerCTEs = ctes
 }
 
 func (b *builder) quote(name string) {
@@ -23,6 +38,10 @@ func (b *builder) buildColumn(col Column) error {
 	case nil:
 		fd, ok := b.model.FieldMap[col.Name]
 		if !ok {
+			if _, isAlias := b.aliases[col.Name]; isAlias {
+				b.quote(col.Name)
+				return nil
+			}
 			return errs.NewErrUnknownField(col.Name)
 		}
 		b.quote(fd.ColName)
@@ -50,11 +69,185 @@ func (b *builder) buildColumn(col Column) error {
 			b.quote(col.alias)
 		}
 		return nil
+	case Subquery:
+		colName, err := b.subqueryColumn(table, col.Name)
+		if err != nil {
+			return err
+		}
+		if table.alias != "" {
+			b.quote(table.alias)
+			b.sb.WriteByte('.')
+		}
+		b.quote(colName)
+		if col.alias != "" {
+			b.sb.WriteString(" AS ")
+			b.quote(col.alias)
+		}
+		return nil
+	case CommonTable:
+		colName, _, err := b.cteColumn(table, col.Name)
+		if err != nil {
+			return err
+		}
+		if table.alias != "" {
+			b.quote(table.alias)
+		} else {
+			b.quote(table.name)
+		}
+		b.sb.WriteByte('.')
+		b.quote(colName)
+		if col.alias != "" {
+			b.sb.WriteString(" AS ")
+			b.quote(col.alias)
+		}
+		return nil
+	case Join:
+		tbl, ok := b.findJoinTable(table, col.Name)
+		if !ok {
+			return errs.NewErrUnknownField(col.Name)
+		}
+		col.Table = tbl
+		return b.buildColumn(col)
 	default:
 		return errs.NewErrUnsupportedTable(col.Name)
 	}
 }
 
+// findJoinTable 在 JOIN 里面找到第一个有这个字段的表，
+// 没有元数据的公共表表达式不参与查找
+func (b *builder) findJoinTable(j Join, name string) (TableReference, bool) {
+	for _, tbl := range []TableReference{j.left, j.right} {
+		switch t := tbl.(type) {
+		case Table:
+			m, err := b.r.Get(t.entity)
+			if err != nil {
+				continue
+			}
+			if _, ok := m.FieldMap[name]; ok {
+				return t, true
+			}
+		case Subquery:
+			if _, err := b.subqueryColumn(t, name); err == nil {
+				return t, true
+			}
+		case CommonTable:
+			if _, resolved, err := b.cteColumn(t, name); resolved && err == nil {
+				return t, true
+			}
+		case Join:
+			if res, ok := b.findJoinTable(t, name); ok {
+				return res, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// subqueryColumn 找到子查询对外暴露的列名。
+// 子查询指定了列的时候，只能使用这些列或者它们的别名
+func (b *builder) subqueryColumn(sub Subquery, name string) (string, error) {
+	if len(sub.columns) == 0 {
+		fd, ok := sub.model.FieldMap[name]
+		if !ok {
+			return "", errs.NewErrUnknownField(name)
+		}
+		return fd.ColName, nil
+	}
+	for _, col := range sub.columns {
+		if alias := aliasOf(col); alias == name {
+			return alias, nil
+		}
+		c, ok := col.(Column)
+		if !ok || c.Name != name {
+			continue
+		}
+		if c.alias != "" {
+			return c.alias, nil
+		}
+		m := sub.model
+		if t, ok := c.Table.(Table); ok {
+			tm, err := b.r.Get(t.entity)
+			if err != nil {
+				return "", err
+			}
+			m = tm
+		}
+		fd, ok := m.FieldMap[name]
+		if !ok {
+			return "", errs.NewErrUnknownField(name)
+		}
+		return fd.ColName, nil
+	}
+	return "", errs.NewErrUnknownField(name)
+}
+
+// cteColumn 找到公共表表达式对外暴露的列名，resolved 表示是否按照元数据找到的。
+// 声明了列名的时候只能使用这些列名；主体是 Selector 或者组合查询的时候和子查询一样查找；
+// 其它情况，例如主体是 RawQuery，没有元数据，name 直接作为列名使用
+func (b *builder) cteColumn(ct CommonTable, name string) (colName string, resolved bool, err error) {
+	cte, ok := b.ctes[ct.name]
+	if !ok {
+		return name, false, nil
+	}
+	if len(cte.columns) > 0 {
+		for _, col := range cte.columns {
+			if col == name {
+				return col, true, nil
+			}
+		}
+		return "", true, errs.NewErrUnknownField(name)
+	}
+	src, ok := cte.q.(resultSource)
+	if !ok {
+		return name, false, nil
+	}
+	sub, ok := src.resultSubquery(ct.name)
+	if !ok {
+		return name, false, nil
+	}
+	colName, err = b.subqueryColumn(sub, name)
+	return colName, true, err
+}
+
+// buildSubquery 把子查询构造在当前位置，参数按照出现的顺序合并
+func (b *builder) buildSubquery(sub Subquery) error {
+	return b.buildNested(sub.s)
+}
+
+// buildNested 把查询用括号包起来构造在当前位置
+func (b *builder) buildNested(nested QueryBuilder) error {
+	if inheritor, ok := nested.(cteInheritor); ok {
+		inheritor.inheritCTEs(b.ctes)
+	}
+	q, err := nested.Build()
+	if err != nil {
+		return err
+	}
+	b.sb.WriteByte('(')
+	b.sb.WriteString(strings.TrimSuffix(q.SQL, ";"))
+	b.sb.WriteByte(')')
+	b.addArgs(q.Args...)
+	return nil
+}
+
+func (b *builder) buildAggregate(a Aggregate) error {
+	b.sb.WriteString(a.fn)
+	b.sb.WriteByte('(')
+	if a.distinct {
+		b.sb.WriteString("DISTINCT ")
+	}
+	if err := b.buildExpression(a.arg); err != nil {
+		return err
+	}
+	b.sb.WriteByte(')')
+	// 聚合函数的别名
+	if a.alias != "" {
+		b.sb.WriteString(" AS ")
+		b.quote(a.alias)
+	}
+	return nil
+}
+
 func (b *builder) addArgs(vals ...any) {
 	if len(vals) == 0 {
 		return
@@ -93,7 +286,10 @@ func (b *builder) buildExpression(expr Expression) error {
 		if exp.op != "" {
 			b.sb.WriteByte(' ')
 			b.sb.WriteString(exp.op.String())
-			b.sb.WriteByte(' ')
+			// IS NULL 这种没有右边的表达式
+			if exp.right != nil {
+				b.sb.WriteByte(' ')
+			}
 		}
 
 		_, ok = exp.right.(Predicate)
@@ -115,62 +311,95 @@ func (b *builder) buildExpression(expr Expression) error {
 		b.sb.WriteByte('?')
 		b.addArgs(exp.val)
 		return nil
+	case values:
+		b.sb.WriteByte('(')
+		for i, val := range exp.vals {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			b.sb.WriteByte('?')
+			b.addArgs(val)
+		}
+		b.sb.WriteByte(')')
+		return nil
+	case binaryExpr:
+		return b.buildBinaryExpr(exp)
+	case MathExpr:
+		return b.buildBinaryExpr(binaryExpr(exp))
+	case Subquery:
+		return b.buildSubquery(exp)
 	case RawExpr:
 		b.sb.WriteByte('(')
 		b.sb.WriteString(exp.raw)
 		b.addArgs(exp.args...)
 		b.sb.WriteByte(')')
 		return nil
+	case Aggregate:
+		exp.alias = ""
+		return b.buildAggregate(exp)
+	case WindowFunc:
+		exp.alias = ""
+		return b.buildWindowFunc(exp)
+	case CaseExpr:
+		exp.alias = ""
+		return b.buildCase(exp)
+	case FuncExpr:
+		exp.alias = ""
+		return b.buildFunc(exp)
 	default:
 		return errs.ErrInvalidExpression
 	}
 }
 
-//func (b *builder) buildBinaryExpr(e binaryExpr) error {
-//	err := b.buildSubExpr(e.left)
-//	if err != nil {
-//		return err
-//	}
-//	if e.op != "" {
-//		b.sb.WriteByte(' ')
-//		b.sb.WriteString(e.op.String())
-//	}
-//	if e.right != nil {
-//		b.sb.WriteByte(' ')
-//		return b.buildSubExpr(e.right)
-//	}
-//	return nil
-//}
-
-//func (b *builder) buildSubExpr(subExpr Expression) error {
-//	switch sub := subExpr.(type) {
-//	case MathExpr:
-//		_ = b.sb.WriteByte('(')
-//		if err := b.buildBinaryExpr(binaryExpr(sub)); err != nil {
-//			return err
-//		}
-//		_ = b.sb.WriteByte(')')
-//	case binaryExpr:
-//		_ = b.sb.WriteByte('(')
-//		if err := b.buildBinaryExpr(sub); err != nil {
-//			return err
-//		}
-//		_ = b.sb.WriteByte(')')
-//	case Predicate:
-//		_ = b.sb.WriteByte('(')
-//		if err := b.buildBinaryExpr(binaryExpr(sub)); err != nil {
-//			return err
-//		}
-//		_ = b.sb.WriteByte(')')
-//	default:
-//		if err := b.buildExpression(sub); err != nil {
-//			return err
-//		}
-//	}
-//	return nil
-//}
+func (b *builder) buildBinaryExpr(e binaryExpr) error {
+	err := b.buildSubExpr(e.left)
+	if err != nil {
+		return err
+	}
+	if e.op != "" {
+		b.sb.WriteByte(' ')
+		b.sb.WriteString(e.op.String())
+	}
+	if e.right != nil {
+		b.sb.WriteByte(' ')
+		return b.buildSubExpr(e.right)
+	}
+	return nil
+}
+
+// buildSubExpr 嵌套的表达式需要加括号，保证运算的优先级
+func (b *builder) buildSubExpr(subExpr Expression) error {
+	switch sub := subExpr.(type) {
+	case MathExpr:
+		sub.alias = ""
+		_ = b.sb.WriteByte('(')
+		if err := b.buildBinaryExpr(binaryExpr(sub)); err != nil {
+			return err
+		}
+		_ = b.sb.WriteByte(')')
+	case binaryExpr:
+		_ = b.sb.WriteByte('(')
+		if err := b.buildBinaryExpr(sub); err != nil {
+			return err
+		}
+		_ = b.sb.WriteByte(')')
+	case Predicate:
+		_ = b.sb.WriteByte('(')
+		if err := b.buildExpression(sub); err != nil {
+			return err
+		}
+		_ = b.sb.WriteByte(')')
+	default:
+		if err := b.buildExpression(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 func (b *builder) reset() {
 	b.sb.Reset()
 	b.args = nil
+	b.aliases = nil
+	b.ctes = b.outerCTEs
 }
@@ -0,0 +1,122 @@
+package gsql
+
+import "github.com/DaHuangQwQ/gsql/internal/errs"
+
+// CaseExpr CASE WHEN ... THEN ... ELSE ... END 表达式
+type CaseExpr struct {
+	whens []caseWhen
+	els   Expression
+	alias string
+}
+
+type caseWhen struct {
+	cond Predicate
+	then Expression
+}
+
+// Case 构造 CASE 表达式，例如 Case().When(C("Age").Lt(18), "child").Else("adult")
+func Case() CaseExpr {
+	return CaseExpr{}
+}
+
+// When 满足 p 的时候取 val，val 可以是值，也可以是表达式
+func (c CaseExpr) When(p Predicate, val any) CaseExpr {
+	whens := make([]caseWhen, 0, len(c.whens)+1)
+	whens = append(whens, c.whens...)
+	c.whens = append(whens, caseWhen{
+		cond: p,
+		then: valueOf(val),
+	})
+	return c
+}
+
+func (c CaseExpr) Else(val any) CaseExpr {
+	c.els = valueOf(val)
+	return c
+}
+
+func (c CaseExpr) As(alias string) CaseExpr {
+	c.alias = alias
+	return c
+}
+
+func (c CaseExpr) Eq(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opEQ,
+		right: valueOf(arg),
+	}
+}
+
+func (c CaseExpr) Ne(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opNE,
+		right: valueOf(arg),
+	}
+}
+
+func (c CaseExpr) Lt(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLT,
+		right: valueOf(arg),
+	}
+}
+
+func (c CaseExpr) Le(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLE,
+		right: valueOf(arg),
+	}
+}
+
+func (c CaseExpr) Gt(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opGT,
+		right: valueOf(arg),
+	}
+}
+
+func (c CaseExpr) Ge(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opGE,
+		right: valueOf(arg),
+	}
+}
+
+func (c CaseExpr) expr() {}
+
+func (c CaseExpr) selectable() {}
+
+func (b *builder) buildCase(c CaseExpr) error {
+	if len(c.whens) == 0 {
+		return errs.ErrEmptyCase
+	}
+	b.sb.WriteString("CASE")
+	for _, w := range c.whens {
+		b.sb.WriteString(" WHEN ")
+		if err := b.buildExpression(w.cond); err != nil {
+			return err
+		}
+		b.sb.WriteString(" THEN ")
+		if err := b.buildExpression(w.then); err != nil {
+			return err
+		}
+	}
+	if c.els != nil {
+		b.sb.WriteString(" ELSE ")
+		if err := b.buildExpression(c.els); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteString(" END")
+	if c.alias != "" {
+		b.sb.WriteString(" AS ")
+		b.quote(c.alias)
+	}
+	return nil
+}
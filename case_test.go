@@ -0,0 +1,116 @@
+package gsql
+
+import (
+	"database/sql"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCaseExpr(t *testing.T) {
+	db := memoryDB(t)
+	sqliteDB := memoryDB(t, WithDialect(DialectSQLite))
+	testCases := []struct {
+		name      string
+		b         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "select alias",
+			b: NewSelector[TestModel](db).Select(C("Id"),
+				Case().When(C("Age").Lt(18), "child").When(C("Age").Lt(60), "adult").Else("senior").As("stage")),
+			wantQuery: &Query{
+				SQL:  "SELECT `id`,CASE WHEN `age` < ? THEN ? WHEN `age` < ? THEN ? ELSE ? END AS `stage` FROM `test_model`;",
+				Args: []any{18, "child", 60, "adult", "senior"},
+			},
+		},
+		{
+			name: "without else",
+			b:    NewSelector[TestModel](db).Select(Case().When(C("LastName").IsNull(), C("FirstName"))),
+			wantQuery: &Query{
+				SQL: "SELECT CASE WHEN `last_name` IS NULL THEN `first_name` END FROM `test_model`;",
+			},
+		},
+		{
+			name: "where",
+			b: NewSelector[TestModel](db).Where(C("Id").Gt(10),
+				Case().When(C("Age").Ge(18), 1).Else(0).Eq(1)),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` > ?) AND (CASE WHEN `age` >= ? THEN ? ELSE ? END = ?);",
+				Args: []any{10, 18, 1, 0, 1},
+			},
+		},
+		{
+			name: "order by",
+			b: NewSelector[TestModel](db).Where(C("Id").Gt(10)).
+				OrderBy(Asc(Case().When(C("FirstName").Eq("vip"), 0).Else(1)), Desc(C("Id"))),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE `id` > ? " +
+					"ORDER BY CASE WHEN `first_name` = ? THEN ? ELSE ? END ASC,`id` DESC;",
+				Args: []any{10, "vip", 0, 1},
+			},
+		},
+		{
+			name: "order by alias",
+			b: NewSelector[TestModel](db).Select(C("Id"), Case().When(C("Age").Lt(18), 1).Else(0).As("minor")).
+				OrderBy(Desc(C("minor"))),
+			wantQuery: &Query{
+				SQL:  "SELECT `id`,CASE WHEN `age` < ? THEN ? ELSE ? END AS `minor` FROM `test_model` ORDER BY `minor` DESC;",
+				Args: []any{18, 1, 0},
+			},
+		},
+		{
+			name: "update",
+			b: NewUpdater[TestModel](db).
+				Set(Assign("Age", Case().When(C("Age").Lt(18), 18).Else(C("Age").Add(1)))).
+				Where(C("Id").Eq(1)),
+			wantQuery: &Query{
+				SQL:  "UPDATE `test_model` SET `age`=CASE WHEN `age` < ? THEN ? ELSE `age` + ? END WHERE `id` = ?;",
+				Args: []any{18, 18, 1, 1},
+			},
+		},
+		{
+			name: "upsert",
+			b: NewInserter[TestModel](sqliteDB).Values(&TestModel{Id: 1, Age: 18}).
+				OnDuplicateKey().ConflictColumns("Id").
+				Update(Assign("Age", Case().When(C("Age").Gt(100), 100).Else(C("Age")))),
+			wantQuery: &Query{
+				SQL: "INSERT INTO `test_model`(`id`,`first_name`,`last_name`,`age`) VALUES (?,?,?,?) " +
+					"ON CONFLICT(`id`) DO UPDATE SET `age`=CASE WHEN `age` > ? THEN ? ELSE `age` END;",
+				Args: []any{int64(1), "", (*sql.NullString)(nil), int8(18), 100, 100},
+			},
+		},
+		{
+			name:    "empty case",
+			b:       NewSelector[TestModel](db).Select(Case().Else(1)),
+			wantErr: errs.ErrEmptyCase,
+		},
+		{
+			name:    "invalid column",
+			b:       NewSelector[TestModel](db).Select(Case().When(C("Invalid").Eq(1), 1)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.b.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestCaseExpr_When(t *testing.T) {
+	// When 不能修改原来的表达式
+	base := Case().When(C("Age").Lt(18), "child")
+	c1 := base.When(C("Age").Lt(60), "adult")
+	c2 := base.When(C("Age").Lt(30), "young")
+	assert.Len(t, base.whens, 1)
+	assert.Equal(t, valueOf("adult"), c1.whens[1].then)
+	assert.Equal(t, valueOf("young"), c2.whens[1].then)
+}
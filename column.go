@@ -1,5 +1,7 @@
 package gsql
 
+import "reflect"
+
 type Column struct {
 	Name  string
 	alias string
@@ -18,6 +20,169 @@ func (c Column) Eq(arg any) Predicate {
 	}
 }
 
+func (c Column) Ne(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opNE,
+		right: valueOf(arg),
+	}
+}
+
+func (c Column) Lt(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLT,
+		right: valueOf(arg),
+	}
+}
+
+func (c Column) Le(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLE,
+		right: valueOf(arg),
+	}
+}
+
+func (c Column) Gt(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opGT,
+		right: valueOf(arg),
+	}
+}
+
+func (c Column) Ge(arg any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opGE,
+		right: valueOf(arg),
+	}
+}
+
+func (c Column) Like(pattern any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opLike,
+		right: valueOf(pattern),
+	}
+}
+
+func (c Column) NotLike(pattern any) Predicate {
+	return Predicate{
+		left:  c,
+		op:    opNotLike,
+		right: valueOf(pattern),
+	}
+}
+
+// In 可以传入多个值，也可以直接传入一个切片或者一个子查询。
+// 没有值的时候恒为假，避免生成 IN () 这种非法的 SQL
+func (c Column) In(vals ...any) Predicate {
+	if sub, ok := subqueryOf(vals); ok {
+		return Predicate{
+			left:  c,
+			op:    opIn,
+			right: sub,
+		}
+	}
+	vals = flatten(vals)
+	if len(vals) == 0 {
+		return Raw("1=0").AsPredicate()
+	}
+	return Predicate{
+		left:  c,
+		op:    opIn,
+		right: values{vals: vals},
+	}
+}
+
+// NotIn 没有值的时候恒为真
+func (c Column) NotIn(vals ...any) Predicate {
+	if sub, ok := subqueryOf(vals); ok {
+		return Predicate{
+			left:  c,
+			op:    opNotIn,
+			right: sub,
+		}
+	}
+	vals = flatten(vals)
+	if len(vals) == 0 {
+		return Raw("1=1").AsPredicate()
+	}
+	return Predicate{
+		left:  c,
+		op:    opNotIn,
+		right: values{vals: vals},
+	}
+}
+
+func (c Column) Between(start any, end any) Predicate {
+	return Predicate{
+		left: c,
+		op:   opBetween,
+		right: binaryExpr{
+			left:  valueOf(start),
+			op:    opAND,
+			right: valueOf(end),
+		},
+	}
+}
+
+func (c Column) IsNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opIsNull,
+	}
+}
+
+func (c Column) IsNotNull() Predicate {
+	return Predicate{
+		left: c,
+		op:   opIsNotNull,
+	}
+}
+
+func (c Column) Add(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opAdd,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Multi(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opMulti,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Div(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
+func (c Column) Mod(val any) MathExpr {
+	return MathExpr{
+		left:  c,
+		op:    opMod,
+		right: valueOf(val),
+	}
+}
+
 func (c Column) As(alias string) Column {
 	return Column{
 		Name:  c.Name,
@@ -35,6 +200,33 @@ func valueOf(arg any) Expression {
 	}
 }
 
+func subqueryOf(vals []any) (Subquery, bool) {
+	if len(vals) != 1 {
+		return Subquery{}, false
+	}
+	sub, ok := vals[0].(Subquery)
+	return sub, ok
+}
+
+// flatten 只有一个切片参数的时候，把切片展开
+func flatten(vals []any) []any {
+	if len(vals) != 1 {
+		return vals
+	}
+	if _, ok := vals[0].([]byte); ok {
+		return vals
+	}
+	val := reflect.ValueOf(vals[0])
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return vals
+	}
+	res := make([]any, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		res = append(res, val.Index(i).Interface())
+	}
+	return res
+}
+
 func (c Column) expr() {}
 
 func (c Column) selectable() {}
@@ -0,0 +1,327 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"strings"
+)
+
+// CompoundSelector 使用 UNION、UNION ALL、INTERSECT 和 EXCEPT 组合多个查询，
+// ORDER BY 和 LIMIT 作用在组合之后的结果上
+type CompoundSelector[T any] struct {
+	builder
+	first   QueryBuilder
+	parts   []compoundPart
+	orderBy []OrderBy
+	limit   int
+	offset  int
+
+	session Session
+}
+
+type compoundPart struct {
+	typ string
+	q   QueryBuilder
+}
+
+// columnCounter 返回查询的列数，无法确定的时候返回 false
+type columnCounter interface {
+	columnCount() (int, bool)
+}
+
+// compoundChecker 检查查询能不能不加括号作为组合查询的一部分。
+// SQLite 不支持带括号的组合查询，所以带有 ORDER BY、LIMIT 这些子句的查询不能直接拼接
+type compoundChecker interface {
+	checkCompoundPart(first bool) error
+}
+
+// aliasProvider 返回查询里面声明的别名，组合之后的结果使用第一个查询的别名
+type aliasProvider interface {
+	selectAliases() map[string]struct{}
+}
+
+func (s *Selector[T]) Union(q QueryBuilder) *CompoundSelector[T] {
+	return s.compound("UNION", q)
+}
+
+func (s *Selector[T]) UnionAll(q QueryBuilder) *CompoundSelector[T] {
+	return s.compound("UNION ALL", q)
+}
+
+func (s *Selector[T]) Intersect(q QueryBuilder) *CompoundSelector[T] {
+	return s.compound("INTERSECT", q)
+}
+
+func (s *Selector[T]) Except(q QueryBuilder) *CompoundSelector[T] {
+	return s.compound("EXCEPT", q)
+}
+
+func (s *Selector[T]) compound(typ string, q QueryBuilder) *CompoundSelector[T] {
+	return &CompoundSelector[T]{
+		builder: builder{
+			core:   s.core,
+			sb:     strings.Builder{},
+			quoter: s.quoter,
+		},
+		first:   s,
+		parts:   []compoundPart{{typ: typ, q: q}},
+		session: s.session,
+	}
+}
+
+func (s *Selector[T]) columnCount() (int, bool) {
+	if len(s.columns) > 0 {
+		return len(s.columns), true
+	}
+	switch t := s.table.(type) {
+	case nil:
+		return len(s.model.Fields), true
+	case Table:
+		m, err := s.r.Get(t.entity)
+		if err != nil {
+			return 0, false
+		}
+		return len(m.Fields), true
+	default:
+		return 0, false
+	}
+}
+
+// checkCompoundPart WITH 只能出现在第一个查询里面，这个时候它作用于整个组合查询
+func (s *Selector[T]) checkCompoundPart(first bool) error {
+	switch {
+	case len(s.ctes) > 0 && !first:
+		return errs.NewErrInvalidCompoundPart("WITH")
+	case len(s.orderBy) > 0:
+		return errs.NewErrInvalidCompoundPart("ORDER BY")
+	case s.limit > 0 || s.offset > 0:
+		return errs.NewErrInvalidCompoundPart("LIMIT/OFFSET")
+	case s.lock.strength != "":
+		return errs.NewErrInvalidCompoundPart("FOR " + s.lock.strength)
+	case s.emulateFullJoin():
+		// 模拟出来的 UNION ALL 不加括号会和其它部分混在一起
+		return errs.NewErrInvalidCompoundPart("emulated FULL OUTER JOIN")
+	default:
+		return nil
+	}
+}
+
+// checkCompoundPart 嵌套的组合查询不能有 ORDER BY 和 LIMIT，
+// 它们应该设置在最外层的组合查询上面
+func (c *CompoundSelector[T]) checkCompoundPart(bool) error {
+	switch {
+	case len(c.orderBy) > 0:
+		return errs.NewErrInvalidCompoundPart("ORDER BY")
+	case c.limit > 0 || c.offset > 0:
+		return errs.NewErrInvalidCompoundPart("LIMIT/OFFSET")
+	default:
+		return nil
+	}
+}
+
+func (c *CompoundSelector[T]) selectAliases() map[string]struct{} {
+	if p, ok := c.first.(aliasProvider); ok {
+		return p.selectAliases()
+	}
+	return nil
+}
+
+// resultSubquery 组合查询结果的列就是第一个查询的列
+func (c *CompoundSelector[T]) resultSubquery(alias string) (Subquery, bool) {
+	src, ok := c.first.(resultSource)
+	if !ok {
+		return Subquery{}, false
+	}
+	return src.resultSubquery(alias)
+}
+
+// inheritCTEs 各个查询都可以引用外层声明的公共表表达式，
+// 例如递归的公共表表达式里面引用自身
+func (c *CompoundSelector[T]) inheritCTEs(ctes map[string]commonTableExpr) {
+	c.outerCTEs = ctes
+	if inheritor, ok := c.first.(cteInheritor); ok {
+		inheritor.inheritCTEs(ctes)
+	}
+	for _, part := range c.parts {
+		if inheritor, ok := part.q.(cteInheritor); ok {
+			inheritor.inheritCTEs(ctes)
+		}
+	}
+}
+
+func (c *CompoundSelector[T]) Union(q QueryBuilder) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart{typ: "UNION", q: q})
+	return c
+}
+
+func (c *CompoundSelector[T]) UnionAll(q QueryBuilder) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart{typ: "UNION ALL", q: q})
+	return c
+}
+
+func (c *CompoundSelector[T]) Intersect(q QueryBuilder) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart{typ: "INTERSECT", q: q})
+	return c
+}
+
+func (c *CompoundSelector[T]) Except(q QueryBuilder) *CompoundSelector[T] {
+	c.parts = append(c.parts, compoundPart{typ: "EXCEPT", q: q})
+	return c
+}
+
+func (c *CompoundSelector[T]) OrderBy(obs ...OrderBy) *CompoundSelector[T] {
+	c.orderBy = obs
+	return c
+}
+
+func (c *CompoundSelector[T]) Limit(limit int) *CompoundSelector[T] {
+	c.limit = limit
+	return c
+}
+
+func (c *CompoundSelector[T]) Offset(offset int) *CompoundSelector[T] {
+	c.offset = offset
+	return c
+}
+
+func (c *CompoundSelector[T]) Build() (*Query, error) {
+	c.reset()
+	// 复制一份，resultColumn 会往里面加入列名
+	c.aliases = make(map[string]struct{})
+	for alias := range c.selectAliases() {
+		c.aliases[alias] = struct{}{}
+	}
+
+	cnt, known := c.count(c.first)
+	if err := c.buildPart(c.first, true); err != nil {
+		return nil, err
+	}
+	for _, part := range c.parts {
+		if partCnt, ok := c.count(part.q); known && ok && partCnt != cnt {
+			return nil, errs.NewErrColumnCountMismatch(cnt, partCnt)
+		}
+		c.sb.WriteByte(' ')
+		c.sb.WriteString(part.typ)
+		c.sb.WriteByte(' ')
+		if err := c.buildPart(part.q, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.orderBy) > 0 {
+		c.sb.WriteString(" ORDER BY ")
+		for i, ob := range c.orderBy {
+			if i > 0 {
+				c.sb.WriteByte(',')
+			}
+			if col, ok := ob.expr.(Column); ok {
+				var err error
+				if ob.expr, err = c.resultColumn(col); err != nil {
+					return nil, err
+				}
+			}
+			if err := c.dialect.buildOrderBy(&c.builder, ob); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := c.dialect.buildLimit(&c.builder, c.limit, c.offset); err != nil {
+		return nil, err
+	}
+
+	c.sb.WriteByte(';')
+
+	return &Query{
+		SQL:  c.sb.String(),
+		Args: c.args,
+	}, nil
+}
+
+// resultColumn 组合之后的结果上面没有表，ORDER BY 里面带表的列只能使用列名。
+// 列名作为结果里面的名字加入 aliases，这样构造的时候不会再加上表名
+func (c *CompoundSelector[T]) resultColumn(col Column) (Column, error) {
+	switch table := col.Table.(type) {
+	case nil:
+		return col, nil
+	case Table:
+		m, err := c.r.Get(table.entity)
+		if err != nil {
+			return Column{}, err
+		}
+		fd, ok := m.FieldMap[col.Name]
+		if !ok {
+			return Column{}, errs.NewErrUnknownField(col.Name)
+		}
+		c.aliases[fd.ColName] = struct{}{}
+		return Column{Name: fd.ColName}, nil
+	case Subquery:
+		colName, err := c.subqueryColumn(table, col.Name)
+		if err != nil {
+			return Column{}, err
+		}
+		c.aliases[colName] = struct{}{}
+		return Column{Name: colName}, nil
+	case Join:
+		tbl, ok := c.findJoinTable(table, col.Name)
+		if !ok {
+			return Column{}, errs.NewErrUnknownField(col.Name)
+		}
+		col.Table = tbl
+		return c.resultColumn(col)
+	default:
+		return Column{}, errs.NewErrUnsupportedTable(col.Name)
+	}
+}
+
+func (c *CompoundSelector[T]) count(q QueryBuilder) (int, bool) {
+	counter, ok := q.(columnCounter)
+	if !ok {
+		return 0, false
+	}
+	return counter.columnCount()
+}
+
+// buildPart 各个查询不加括号，SQLite 不支持带括号的组合查询
+func (c *CompoundSelector[T]) buildPart(q QueryBuilder, first bool) error {
+	if checker, ok := q.(compoundChecker); ok {
+		if err := checker.checkCompoundPart(first); err != nil {
+			return err
+		}
+	}
+	query, err := q.Build()
+	if err != nil {
+		return err
+	}
+	c.sb.WriteString(strings.TrimSuffix(query.SQL, ";"))
+	c.addArgs(query.Args...)
+	return nil
+}
+
+func (c *CompoundSelector[T]) Get(ctx context.Context) (*T, error) {
+	res := get[T](ctx, c.session, c.core, &QueryContext{
+		Type:    TypeSelect,
+		Builder: c,
+		Model:   c.model,
+	})
+
+	if res.Result != nil {
+		return res.Result.(*T), nil
+	}
+
+	return nil, res.Err
+}
+
+func (c *CompoundSelector[T]) GetMulti(ctx context.Context) ([]*T, error) {
+	res := getMulti[T](ctx, c.session, c.core, &QueryContext{
+		Type:    TypeSelect,
+		Builder: c,
+		Model:   c.model,
+	})
+
+	if res.Result != nil {
+		return res.Result.([]*T), nil
+	}
+
+	return nil, res.Err
+}
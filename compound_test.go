@@ -0,0 +1,212 @@
+package gsql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCompoundSelector_Build(t *testing.T) {
+	db := memoryDB(t)
+	type ArchivedTestModel struct {
+		Id        int64
+		FirstName string
+		LastName  *sql.NullString
+		Age       int8
+	}
+
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "union",
+			s: NewSelector[TestModel](db).Where(C("Age").Gt(18)).
+				Union(NewSelector[ArchivedTestModel](db).Where(C("Age").Gt(20))),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `age` > ? UNION SELECT * FROM `archived_test_model` WHERE `age` > ?;",
+				Args: []any{18, 20},
+			},
+		},
+		{
+			name: "union all",
+			s: NewSelector[TestModel](db).Select(C("Id"), C("FirstName")).
+				UnionAll(NewSelector[ArchivedTestModel](db).Select(C("Id"), C("FirstName"))),
+			wantQuery: &Query{
+				SQL: "SELECT `id`,`first_name` FROM `test_model` UNION ALL SELECT `id`,`first_name` FROM `archived_test_model`;",
+			},
+		},
+		{
+			name: "intersect except",
+			s: NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").Eq(1)).
+				Intersect(NewSelector[TestModel](db).Select(C("Id")).Where(C("Age").Eq(2))).
+				Except(NewSelector[ArchivedTestModel](db).Select(C("Id")).Where(C("Age").Eq(3))),
+			wantQuery: &Query{
+				SQL: "SELECT `id` FROM `test_model` WHERE `age` = ? " +
+					"INTERSECT SELECT `id` FROM `test_model` WHERE `age` = ? " +
+					"EXCEPT SELECT `id` FROM `archived_test_model` WHERE `age` = ?;",
+				Args: []any{1, 2, 3},
+			},
+		},
+		{
+			name: "order by limit",
+			s: NewSelector[TestModel](db).Where(C("Age").Gt(18)).
+				Union(NewSelector[ArchivedTestModel](db).Where(C("Age").Gt(20))).
+				Union(RawQuery[TestModel](db, "SELECT * FROM `test_model_2` WHERE `age` > ?", 22)).
+				OrderBy(Desc(C("Age"))).Limit(10).Offset(5),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE `age` > ? " +
+					"UNION SELECT * FROM `archived_test_model` WHERE `age` > ? " +
+					"UNION SELECT * FROM `test_model_2` WHERE `age` > ? " +
+					"ORDER BY `age` DESC LIMIT ? OFFSET ?;",
+				Args: []any{18, 20, 22, 10, 5},
+			},
+		},
+		{
+			// 组合之后的结果只能使用第一个查询的别名排序
+			name: "order by alias",
+			s: NewSelector[TestModel](db).Select(C("FirstName").As("n")).
+				Union(NewSelector[ArchivedTestModel](db).Select(C("FirstName"))).
+				OrderBy(Asc(C("n"))),
+			wantQuery: &Query{
+				SQL: "SELECT `first_name` AS `n` FROM `test_model` UNION SELECT `first_name` FROM `archived_test_model` ORDER BY `n` ASC;",
+			},
+		},
+		{
+			name: "order by table column",
+			s: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				return NewSelector[TestModel](db).From(t1).Select(t1.C("Id")).
+					Union(NewSelector[ArchivedTestModel](db).Select(C("Id"))).
+					OrderBy(Desc(t1.C("Id")))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT `t1`.`id` FROM `test_model` AS `t1` UNION SELECT `id` FROM `archived_test_model` ORDER BY `id` DESC;",
+			},
+		},
+		{
+			name: "order by invalid table column",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[ArchivedTestModel](db)).OrderBy(Asc(TableOf(&TestModel{}).C("Invalid"))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name: "column count mismatch",
+			s: NewSelector[TestModel](db).Select(C("Id")).
+				Union(NewSelector[ArchivedTestModel](db).Select(C("Id"), C("Age"))),
+			wantErr: errs.NewErrColumnCountMismatch(1, 2),
+		},
+		{
+			name: "column count mismatch all columns",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[ArchivedTestModel](db).Select(C("Id"))),
+			wantErr: errs.NewErrColumnCountMismatch(4, 1),
+		},
+		{
+			name: "invalid part",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[ArchivedTestModel](db).Where(C("Invalid").Eq(1))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name: "with on first part",
+			s: NewSelector[TestModel](db).With("t", NewSelector[TestModel](db).Select(C("Id"))).
+				Select(C("Id")).
+				Union(NewSelector[ArchivedTestModel](db).Select(C("Id"))),
+			wantQuery: &Query{
+				SQL: "WITH `t` AS (SELECT `id` FROM `test_model`) SELECT `id` FROM `test_model` " +
+					"UNION SELECT `id` FROM `archived_test_model`;",
+			},
+		},
+		{
+			name: "part with limit",
+			s: NewSelector[TestModel](db).Limit(1).
+				Union(NewSelector[TestModel](db).Limit(2)),
+			wantErr: errs.NewErrInvalidCompoundPart("LIMIT/OFFSET"),
+		},
+		{
+			name: "part with offset",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[TestModel](db).Offset(2)),
+			wantErr: errs.NewErrInvalidCompoundPart("LIMIT/OFFSET"),
+		},
+		{
+			name: "part with order by",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[TestModel](db).OrderBy(Asc(C("Id")))),
+			wantErr: errs.NewErrInvalidCompoundPart("ORDER BY"),
+		},
+		{
+			name: "part with lock",
+			s: NewSelector[TestModel](db).ForUpdate().
+				Union(NewSelector[TestModel](db)),
+			wantErr: errs.NewErrInvalidCompoundPart("FOR UPDATE"),
+		},
+		{
+			name: "part with with",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[TestModel](db).With("t", NewSelector[TestModel](db))),
+			wantErr: errs.NewErrInvalidCompoundPart("WITH"),
+		},
+		{
+			name: "nested compound with order by",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[TestModel](db).Union(NewSelector[TestModel](db)).OrderBy(Asc(C("Id")))),
+			wantErr: errs.NewErrInvalidCompoundPart("ORDER BY"),
+		},
+		{
+			name: "invalid order by",
+			s: NewSelector[TestModel](db).
+				Union(NewSelector[ArchivedTestModel](db)).OrderBy(Asc(C("Invalid"))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestCompoundSelector_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "age"})
+	rows.AddRow("1", "da", "huang", "18")
+	rows.AddRow("2", "xiao", "huang", "20")
+	mock.ExpectQuery("SELECT .* UNION ALL SELECT .*").WithArgs(18, 20).WillReturnRows(rows)
+
+	res, err := NewSelector[TestModel](db).Where(C("Age").Eq(18)).
+		UnionAll(NewSelector[TestModel](db).Where(C("Age").Eq(20))).
+		GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{
+		{
+			Id:        1,
+			FirstName: "da",
+			LastName:  &sql.NullString{String: "huang", Valid: true},
+			Age:       18,
+		},
+		{
+			Id:        2,
+			FirstName: "xiao",
+			LastName:  &sql.NullString{String: "huang", Valid: true},
+			Age:       20,
+		},
+	}, res)
+}
@@ -2,6 +2,7 @@ package gsql
 
 import (
 	"context"
+	"errors"
 	"github.com/DaHuangQwQ/gsql/internal/valuer"
 	"github.com/DaHuangQwQ/gsql/model"
 )
@@ -39,7 +40,16 @@ func getHandler[T any](ctx context.Context, sess Session, c core, qc *QueryConte
 		}
 	}
 
+	defer func() {
+		_ = rows.Close()
+	}()
+
 	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return &QueryResult{
+				Err: err,
+			}
+		}
 		return &QueryResult{
 			Err: ErrNoRows,
 		}
@@ -47,14 +57,212 @@ func getHandler[T any](ctx context.Context, sess Session, c core, qc *QueryConte
 
 	tp := new(T)
 	val := c.creator(c.model, tp)
-	err = val.SetColumns(rows)
+	if err = val.SetColumns(rows); err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
 
 	return &QueryResult{
-		Err:    err,
 		Result: tp,
 	}
 }
 
+func getMulti[T any](ctx context.Context, sess Session, c core, qc *QueryContext) *QueryResult {
+	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		return getMultiHandler[T](ctx, sess, c, qc)
+	}
+	for i := len(c.mdls) - 1; i >= 0; i-- {
+		root = c.mdls[i](root)
+	}
+	return root(ctx, qc)
+}
+
+func getMultiHandler[T any](ctx context.Context, sess Session, c core, qc *QueryContext) *QueryResult {
+	q, err := qc.Builder.Build()
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+
+	rows, err := sess.queryContext(ctx, q.SQL, q.Args...)
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	res := make([]*T, 0, 8)
+	for rows.Next() {
+		tp := new(T)
+		val := c.creator(c.model, tp)
+		if err = val.SetColumns(rows); err != nil {
+			return &QueryResult{
+				Err: err,
+			}
+		}
+		res = append(res, tp)
+	}
+	if err = rows.Err(); err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+
+	if len(res) == 0 {
+		return &QueryResult{
+			Err: ErrNoRows,
+		}
+	}
+
+	return &QueryResult{
+		Result: res,
+	}
+}
+
+func each[T any](ctx context.Context, sess Session, c core, qc *QueryContext, fn func(*T) error) *QueryResult {
+	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		return eachHandler[T](ctx, sess, c, qc, fn)
+	}
+	for i := len(c.mdls) - 1; i >= 0; i-- {
+		root = c.mdls[i](root)
+	}
+	return root(ctx, qc)
+}
+
+// eachHandler 一行一行地扫描，T 和 valuer 在每一行之间复用
+func eachHandler[T any](ctx context.Context, sess Session, c core, qc *QueryContext, fn func(*T) error) *QueryResult {
+	q, err := qc.Builder.Build()
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+
+	rows, err := sess.queryContext(ctx, q.SQL, q.Args...)
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tp := new(T)
+	val := c.creator(c.model, tp)
+	for rows.Next() {
+		var zero T
+		*tp = zero
+		if err = val.SetColumns(rows); err != nil {
+			return &QueryResult{
+				Err: err,
+			}
+		}
+		if err = fn(tp); err != nil {
+			if errors.Is(err, ErrStop) {
+				break
+			}
+			return &QueryResult{
+				Err: err,
+			}
+		}
+	}
+	return &QueryResult{
+		Err: rows.Err(),
+	}
+}
+
+// getScalars 只扫描结果集的第一列，没有数据的时候返回空切片
+func getScalars[V any](ctx context.Context, sess Session, c core, qc *QueryContext) *QueryResult {
+	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		return getScalarsHandler[V](ctx, sess, qc)
+	}
+	for i := len(c.mdls) - 1; i >= 0; i-- {
+		root = c.mdls[i](root)
+	}
+	return root(ctx, qc)
+}
+
+func getScalarsHandler[V any](ctx context.Context, sess Session, qc *QueryContext) *QueryResult {
+	q, err := qc.Builder.Build()
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+
+	rows, err := sess.queryContext(ctx, q.SQL, q.Args...)
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	res := make([]V, 0, 8)
+	for rows.Next() {
+		var v V
+		if err = rows.Scan(&v); err != nil {
+			return &QueryResult{
+				Err: err,
+			}
+		}
+		res = append(res, v)
+	}
+	if err = rows.Err(); err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+
+	return &QueryResult{
+		Result: res,
+	}
+}
+
+func getMaps(ctx context.Context, sess Session, c core, qc *QueryContext) *QueryResult {
+	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
+		return getMapsHandler(ctx, sess, qc)
+	}
+	for i := len(c.mdls) - 1; i >= 0; i-- {
+		root = c.mdls[i](root)
+	}
+	return root(ctx, qc)
+}
+
+// getMapsHandler 结果集的每一行都转成列名到值的映射，不需要模型
+func getMapsHandler(ctx context.Context, sess Session, qc *QueryContext) *QueryResult {
+	q, err := qc.Builder.Build()
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+
+	rows, err := sess.queryContext(ctx, q.SQL, q.Args...)
+	if err != nil {
+		return &QueryResult{
+			Err: err,
+		}
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	res, err := scanMaps(rows)
+	return &QueryResult{
+		Err:    err,
+		Result: res,
+	}
+}
+
 func exec(ctx context.Context, sess Session, c core, qc *QueryContext) *QueryResult {
 	var root Handler = func(ctx context.Context, qc *QueryContext) *QueryResult {
 		return execHandler(ctx, sess, c, qc)
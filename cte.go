@@ -0,0 +1,149 @@
+package gsql
+
+// commonTableExpr WITH 子句里面声明的公共表表达式
+type commonTableExpr struct {
+	name      string
+	columns   []string
+	q         QueryBuilder
+	recursive bool
+}
+
+// CommonTable 在查询里面引用 WITH 声明的公共表表达式
+type CommonTable struct {
+	name  string
+	alias string
+}
+
+// CTE 引用名字为 name 的公共表表达式，可以用在 FROM 和 JOIN 里面
+func CTE(name string) CommonTable {
+	return CommonTable{
+		name: name,
+	}
+}
+
+func (c CommonTable) As(alias string) CommonTable {
+	return CommonTable{
+		name:  c.name,
+		alias: alias,
+	}
+}
+
+// C 引用公共表表达式里面的列。主体是 Selector 或者组合查询的时候，name 和子查询一样是字段名或者别名；
+// With 声明了列名的时候，name 是声明的列名；主体没有元数据的时候，name 直接作为列名使用
+func (c CommonTable) C(name string) Column {
+	return Column{
+		Name:  name,
+		Table: c,
+	}
+}
+
+func (c CommonTable) table() {}
+
+func (c CommonTable) Join(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: right,
+		typ:   "JOIN",
+	}
+}
+
+func (c CommonTable) LeftJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: right,
+		typ:   "LEFT JOIN",
+	}
+}
+
+func (c CommonTable) RightJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: right,
+		typ:   "RIGHT JOIN",
+	}
+}
+
+func (c CommonTable) CrossJoin(right TableReference) Join {
+	return Join{
+		left:  c,
+		right: right,
+		typ:   "CROSS JOIN",
+	}
+}
+
+func (c CommonTable) FullOuterJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  c,
+		right: right,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
+// With 声明公共表表达式，columns 是可选的列名
+func (s *Selector[T]) With(name string, q QueryBuilder, columns ...string) *Selector[T] {
+	s.ctes = append(s.ctes, commonTableExpr{
+		name:    name,
+		columns: columns,
+		q:       q,
+	})
+	return s
+}
+
+// WithRecursive 声明递归的公共表表达式，q 一般是 UNION ALL 组合起来的查询，
+// 在递归的部分里面使用 CTE(name) 引用自身
+func (s *Selector[T]) WithRecursive(name string, q QueryBuilder, columns ...string) *Selector[T] {
+	s.ctes = append(s.ctes, commonTableExpr{
+		name:      name,
+		columns:   columns,
+		q:         q,
+		recursive: true,
+	})
+	return s
+}
+
+// resultSource 返回查询结果的元数据，公共表表达式按照它找到列名
+type resultSource interface {
+	resultSubquery(alias string) (Subquery, bool)
+}
+
+func (b *builder) buildWith(ctes []commonTableExpr) error {
+	// 复制一份，外层声明的公共表表达式可能被其它查询共享
+	scope := make(map[string]commonTableExpr, len(b.ctes)+len(ctes))
+	for name, cte := range b.ctes {
+		scope[name] = cte
+	}
+	for _, cte := range ctes {
+		scope[cte.name] = cte
+	}
+	b.ctes = scope
+
+	b.sb.WriteString("WITH ")
+	for _, cte := range ctes {
+		if cte.recursive {
+			b.sb.WriteString("RECURSIVE ")
+			break
+		}
+	}
+	for i, cte := range ctes {
+		if i > 0 {
+			b.sb.WriteByte(',')
+		}
+		b.quote(cte.name)
+		if len(cte.columns) > 0 {
+			b.sb.WriteByte('(')
+			for j, col := range cte.columns {
+				if j > 0 {
+					b.sb.WriteByte(',')
+				}
+				b.quote(col)
+			}
+			b.sb.WriteByte(')')
+		}
+		b.sb.WriteString(" AS ")
+		if err := b.buildNested(cte.q); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteByte(' ')
+	return nil
+}
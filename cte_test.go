@@ -0,0 +1,172 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSelector_With(t *testing.T) {
+	type Order struct {
+		Id     int64
+		UserId int64
+		Amount int64
+	}
+
+	mysqlDB := memoryDB(t)
+	sqliteDB := memoryDB(t, WithDialect(DialectSQLite))
+
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "with",
+			s: NewSelector[Order](mysqlDB).
+				With("recent", NewSelector[Order](mysqlDB).Where(C("Id").Gt(100))).
+				From(CTE("recent")).Where(C("Amount").Gt(10)),
+			wantQuery: &Query{
+				SQL: "WITH `recent` AS (SELECT * FROM `order` WHERE `id` > ?) " +
+					"SELECT * FROM `recent` WHERE `amount` > ?;",
+				Args: []any{100, 10},
+			},
+		},
+		{
+			name: "multiple with columns",
+			s: func() QueryBuilder {
+				recent := CTE("recent").As("r")
+				total := CTE("total")
+				return NewSelector[Order](sqliteDB).
+					With("recent", NewSelector[Order](sqliteDB).Where(C("Id").Gt(100))).
+					With("total", NewSelector[Order](sqliteDB).Select(C("UserId"), Sum("Amount")).
+						GroupBy(C("UserId")), "user_id", "total").
+					Select(recent.C("Id"), total.C("total")).
+					From(recent.Join(total).On(recent.C("UserId").Eq(total.C("user_id"))))
+			}(),
+			wantQuery: &Query{
+				SQL: "WITH `recent` AS (SELECT * FROM `order` WHERE `id` > ?)," +
+					"`total`(`user_id`,`total`) AS (SELECT `user_id`,SUM(`amount`) FROM `order` GROUP BY `user_id`) " +
+					"SELECT `r`.`id`,`total`.`total` FROM (`recent` AS `r` JOIN `total` ON `r`.`user_id` = `total`.`user_id`);",
+				Args: []any{100},
+			},
+		},
+		{
+			name: "with recursive",
+			s: func() QueryBuilder {
+				n := TableOf(&CteNode{}).As("n")
+				tree := CTE("tree")
+				anchor := NewSelector[CteNode](mysqlDB).Where(C("Id").Eq(1))
+				recursive := NewSelector[CteNode](mysqlDB).
+					Select(n.C("Id"), n.C("ParentId"), n.C("Name")).
+					From(n.Join(tree).On(n.C("ParentId").Eq(tree.C("Id"))))
+				return NewSelector[CteNode](mysqlDB).
+					WithRecursive("tree", anchor.UnionAll(recursive)).
+					From(tree)
+			}(),
+			wantQuery: &Query{
+				SQL: "WITH RECURSIVE `tree` AS (SELECT * FROM `cte_node` WHERE `id` = ? UNION ALL " +
+					"SELECT `n`.`id`,`n`.`parent_id`,`n`.`name` FROM " +
+					"(`cte_node` AS `n` JOIN `tree` ON `n`.`parent_id` = `tree`.`id`)) " +
+					"SELECT * FROM `tree`;",
+				Args: []any{1},
+			},
+		},
+		{
+			// 主体是 Selector 的时候只能使用字段名或者别名
+			name: "invalid cte column",
+			s: NewSelector[Order](mysqlDB).
+				With("recent", NewSelector[Order](mysqlDB)).
+				Select(CTE("recent").C("user_id")).From(CTE("recent")),
+			wantErr: errs.NewErrUnknownField("user_id"),
+		},
+		{
+			name: "invalid declared cte column",
+			s: NewSelector[Order](mysqlDB).
+				With("total", NewSelector[Order](mysqlDB).Select(C("UserId"), Sum("Amount")).
+					GroupBy(C("UserId")), "user_id", "total").
+				Select(CTE("total").C("Total")).From(CTE("total")),
+			wantErr: errs.NewErrUnknownField("Total"),
+		},
+		{
+			name: "cte column alias",
+			s: NewSelector[Order](mysqlDB).
+				With("total", NewSelector[Order](mysqlDB).Select(C("UserId"), Sum("Amount").As("total")).
+					GroupBy(C("UserId"))).
+				Select(CTE("total").C("UserId"), CTE("total").C("total")).From(CTE("total")),
+			wantQuery: &Query{
+				SQL: "WITH `total` AS (SELECT `user_id`,SUM(`amount`) AS `total` FROM `order` GROUP BY `user_id`) " +
+					"SELECT `total`.`user_id`,`total`.`total` FROM `total`;",
+			},
+		},
+		{
+			// 主体没有元数据的时候直接使用列名
+			name: "raw cte column",
+			s: NewSelector[Order](mysqlDB).
+				With("recent", RawQuery[Order](mysqlDB, "SELECT `id` AS `order_id` FROM `order`")).
+				Select(CTE("recent").C("order_id")).From(CTE("recent")),
+			wantQuery: &Query{
+				SQL: "WITH `recent` AS (SELECT `id` AS `order_id` FROM `order`) " +
+					"SELECT `recent`.`order_id` FROM `recent`;",
+			},
+		},
+		{
+			name: "with invalid query",
+			s: NewSelector[Order](mysqlDB).
+				With("recent", NewSelector[Order](mysqlDB).Where(C("Invalid").Gt(100))).
+				From(CTE("recent")),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_WithRecursive_SQLite(t *testing.T) {
+	db := memoryDB(t, WithDialect(DialectSQLite))
+	ctx := context.Background()
+	require.NoError(t, RawQuery[CteNode](db, "CREATE TABLE IF NOT EXISTS `cte_node`("+
+		"`id` INTEGER PRIMARY KEY, `parent_id` INTEGER, `name` TEXT)").Exec(ctx).Err())
+	require.NoError(t, RawQuery[CteNode](db, "DELETE FROM `cte_node`").Exec(ctx).Err())
+	require.NoError(t, NewInserter[CteNode](db).Values(
+		&CteNode{Id: 1, Name: "root"},
+		&CteNode{Id: 2, ParentId: 1, Name: "child"},
+		&CteNode{Id: 3, ParentId: 2, Name: "grandchild"},
+		&CteNode{Id: 4, Name: "other"},
+	).Exec(ctx).Err())
+
+	n := TableOf(&CteNode{}).As("n")
+	tree := CTE("tree")
+	anchor := NewSelector[CteNode](db).Where(C("Id").Eq(1))
+	recursive := NewSelector[CteNode](db).
+		Select(n.C("Id"), n.C("ParentId"), n.C("Name")).
+		From(n.Join(tree).On(n.C("ParentId").Eq(tree.C("Id"))))
+	res, err := NewSelector[CteNode](db).
+		WithRecursive("tree", anchor.UnionAll(recursive)).
+		From(tree).OrderBy(Asc(C("Id"))).
+		GetMulti(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*CteNode{
+		{Id: 1, Name: "root"},
+		{Id: 2, ParentId: 1, Name: "child"},
+		{Id: 3, ParentId: 2, Name: "grandchild"},
+	}, res)
+}
+
+type CteNode struct {
+	Id       int64
+	ParentId int64
+	Name     string
+}
@@ -39,6 +39,7 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 		return nil, err
 	}
 	return &Tx{
+		db: db,
 		tx: tx,
 	}, nil
 }
@@ -1,20 +1,26 @@
 package gsql
 
-import "strings"
+import (
+	"context"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"strings"
+)
 
 type Deleter[T any] struct {
 	builder
 
 	where   []Predicate
+	orderBy []OrderBy
+	limit   int
 	session Session
+
+	// err 注册模型的时候的错误，Build 和 Exec 的时候返回
+	err error
 }
 
 func NewDeleter[T any](session Session) *Deleter[T] {
 	base := session.getCore()
 	m, err := base.r.Register(new(T))
-	if err != nil {
-		return nil
-	}
 	return &Deleter[T]{
 		builder: builder{
 			core: core{
@@ -28,41 +34,99 @@ func NewDeleter[T any](session Session) *Deleter[T] {
 			quoter: base.dialect.quoter(),
 		},
 		session: session,
+		err:     err,
 	}
 }
 
 func (d *Deleter[T]) Build() (*Query, error) {
-	d.builder.sb.WriteString("DELETE FROM ")
-	d.builder.quote(d.model.TableName)
+	if d.err != nil {
+		return nil, d.err
+	}
+	d.reset()
+
+	d.sb.WriteString("DELETE FROM ")
+	d.quote(d.model.TableName)
 
 	if len(d.where) > 0 {
-		d.builder.sb.WriteString(" WHERE ")
+		d.sb.WriteString(" WHERE ")
+		if err := d.buildPredicates(d.where); err != nil {
+			return nil, err
+		}
+	}
 
-		p := d.where[0]
-		for i := 1; i < len(d.where); i++ {
-			p.And(d.where[1])
+	if len(d.orderBy) > 0 || d.limit > 0 {
+		if !d.dialect.supportDeleteLimit() {
+			return nil, errs.NewErrUnsupportedClause("DELETE ... ORDER BY/LIMIT")
 		}
+	}
 
-		er := d.buildExpression(p)
-		if er != nil {
-			return nil, er
+	if len(d.orderBy) > 0 {
+		d.sb.WriteString(" ORDER BY ")
+		for i, ob := range d.orderBy {
+			if i > 0 {
+				d.sb.WriteByte(',')
+			}
+			if err := d.dialect.buildOrderBy(&d.builder, ob); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	d.builder.sb.WriteByte(';')
+	if d.limit > 0 {
+		d.sb.WriteString(" LIMIT ?")
+		d.addArgs(d.limit)
+	}
+
+	d.sb.WriteByte(';')
 
 	return &Query{
-		SQL:  d.builder.sb.String(),
-		Args: d.builder.args,
+		SQL:  d.sb.String(),
+		Args: d.args,
 	}, nil
 }
 
+func (d *Deleter[T]) Exec(ctx context.Context) Result {
+	if d.err != nil {
+		return Result{
+			err: d.err,
+		}
+	}
+	res := exec(ctx, d.session, d.core, &QueryContext{
+		Type:    TypeDelete,
+		Builder: d,
+		Model:   d.model,
+	})
+
+	// execHandler 返回的 Result 里面已经带上了错误
+	if r, ok := res.Result.(Result); ok {
+		return r
+	}
+
+	return Result{
+		err: res.Err,
+	}
+}
+
 func (d *Deleter[T]) Where(p ...Predicate) *Deleter[T] {
 	d.where = p
 	return d
 }
 
+// OrderBy 只有 MySQL 支持 DELETE ... ORDER BY
+func (d *Deleter[T]) OrderBy(obs ...OrderBy) *Deleter[T] {
+	d.orderBy = obs
+	return d
+}
+
+// Limit 只有 MySQL 支持 DELETE ... LIMIT
+func (d *Deleter[T]) Limit(limit int) *Deleter[T] {
+	d.limit = limit
+	return d
+}
+
 func (d *Deleter[T]) From(tableName string) *Deleter[T] {
-	d.model.TableName = tableName
+	if d.err == nil {
+		d.model.TableName = tableName
+	}
 	return d
 }
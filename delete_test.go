@@ -1,12 +1,19 @@
 package gsql
 
 import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
 	"testing"
 )
 
 func TestDeleter_Build(t *testing.T) {
 	db := memoryDB(t, WithDialect(DialectSQLite))
+	mysqlDB := memoryDB(t)
 	testCases := []struct {
 		name      string
 		builder   QueryBuilder
@@ -36,6 +43,39 @@ func TestDeleter_Build(t *testing.T) {
 				Args: []any{16},
 			},
 		},
+		{
+			name: "multiple where",
+			builder: (NewDeleter[TestModel](db)).
+				Where(C("Id").Eq(16), C("Age").Gt(18), C("FirstName").Eq("Tom")),
+			wantQuery: &Query{
+				SQL:  "DELETE FROM `test_model` WHERE ((`id` = ?) AND (`age` > ?)) AND (`first_name` = ?);",
+				Args: []any{16, 18, "Tom"},
+			},
+		},
+		{
+			name: "order by limit",
+			builder: (NewDeleter[TestModel](mysqlDB)).Where(C("Age").Gt(18)).
+				OrderBy(Desc(C("Id"))).Limit(10),
+			wantQuery: &Query{
+				SQL:  "DELETE FROM `test_model` WHERE `age` > ? ORDER BY `id` DESC LIMIT ?;",
+				Args: []any{18, 10},
+			},
+		},
+		{
+			name:    "limit unsupported",
+			builder: (NewDeleter[TestModel](db)).Limit(10),
+			wantErr: errs.NewErrUnsupportedClause("DELETE ... ORDER BY/LIMIT"),
+		},
+		{
+			name:    "invalid column",
+			builder: (NewDeleter[TestModel](db)).Where(C("Invalid").Eq(1)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name:    "invalid model",
+			builder: (NewDeleter[int](db)).From("int").Where(C("Id").Eq(1)),
+			wantErr: errs.ErrInvalidType,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -50,3 +90,40 @@ func TestDeleter_Build(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleter_Exec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+	var types []Type
+	db.Use(func(next Handler) Handler {
+		return func(ctx context.Context, qc *QueryContext) *QueryResult {
+			types = append(types, qc.Type)
+			return next(ctx, qc)
+		}
+	})
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `test_model` WHERE (`id` = ?) AND (`age` > ?);")).
+		WithArgs(1, 18).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE .*").WillReturnError(errors.New("exec error"))
+
+	res := NewDeleter[TestModel](db).Where(C("Id").Eq(1), C("Age").Gt(18)).Exec(context.Background())
+	require.NoError(t, res.Err())
+	affected, err := res.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+
+	res = NewDeleter[TestModel](db).Exec(context.Background())
+	assert.Equal(t, errors.New("exec error"), res.Err())
+
+	res = NewDeleter[TestModel](db).Where(C("Invalid").Eq(1)).Exec(context.Background())
+	assert.Equal(t, errs.NewErrUnknownField("Invalid"), res.Err())
+
+	res = NewDeleter[int](db).Exec(context.Background())
+	assert.Equal(t, errs.ErrInvalidType, res.Err())
+
+	assert.Equal(t, []Type{TypeDelete, TypeDelete, TypeDelete}, types)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -12,14 +12,37 @@ type Dialect interface {
 	quoter() byte
 
 	buildUpsert(b *builder, upsert *Upsert) error
+
+	buildOrderBy(b *builder, ob OrderBy) error
+
+	// buildLimit 构造分页子句，limit 和 offset 为 0 表示没有设置，
+	// 不同语法的数据库（比如 OFFSET ... FETCH）在这里实现自己的分页
+	buildLimit(b *builder, limit int, offset int) error
+
+	// buildFunc 构造函数调用，不同的数据库函数名字和写法不一样
+	buildFunc(b *builder, f FuncExpr) error
+
+	// buildLock 构造 FOR UPDATE 之类的行锁子句，不支持的数据库返回错误
+	buildLock(b *builder, l rowLock) error
+
+	// buildIndexHints 构造表后面的索引提示，不支持的数据库返回错误
+	buildIndexHints(b *builder, hints []indexHint) error
+
+	// buildOptimizerHints 构造 SELECT 后面的优化器提示，不支持的数据库忽略
+	buildOptimizerHints(b *builder, hints []string) error
+
+	// supportFullJoin 不支持 FULL OUTER JOIN 的数据库会使用 UNION 模拟
+	supportFullJoin() bool
+
+	// supportDeleteLimit DELETE 语句是否支持 ORDER BY 和 LIMIT
+	supportDeleteLimit() bool
 }
 
 type standardSQL struct {
 }
 
 func (s standardSQL) quoter() byte {
-	// TODO implement me
-	panic("implement me")
+	return '"'
 }
 
 func (s standardSQL) buildUpsert(b *builder, upsert *Upsert) error {
@@ -27,6 +50,78 @@ func (s standardSQL) buildUpsert(b *builder, upsert *Upsert) error {
 	panic("implement me")
 }
 
+func (s standardSQL) buildOrderBy(b *builder, ob OrderBy) error {
+	if err := b.buildExpression(ob.expr); err != nil {
+		return err
+	}
+	b.sb.WriteByte(' ')
+	b.sb.WriteString(ob.order)
+	if ob.nulls != "" {
+		b.sb.WriteString(" NULLS ")
+		b.sb.WriteString(ob.nulls)
+	}
+	return nil
+}
+
+func (s standardSQL) buildLimit(b *builder, limit int, offset int) error {
+	if limit > 0 {
+		b.sb.WriteString(" LIMIT ?")
+		b.addArgs(limit)
+	}
+	if offset > 0 {
+		b.sb.WriteString(" OFFSET ?")
+		b.addArgs(offset)
+	}
+	return nil
+}
+
+// buildFunc 标准 SQL 的写法
+func (s standardSQL) buildFunc(b *builder, f FuncExpr) error {
+	switch f.name {
+	case funcConcat:
+		return b.buildConcatOperator(f.args)
+	case funcIfNull:
+		return b.buildFuncCall(funcCoalesce, f.args)
+	case funcLength:
+		return b.buildFuncCall("CHAR_LENGTH", f.args)
+	case funcNow:
+		b.sb.WriteString("CURRENT_TIMESTAMP")
+		return nil
+	case funcDate:
+		return b.buildCast(f.args[0], "DATE")
+	case funcCast:
+		return b.buildCast(f.args[0], f.typ)
+	default:
+		return b.buildFuncCall(f.name, f.args)
+	}
+}
+
+func (s standardSQL) buildLock(b *builder, l rowLock) error {
+	b.sb.WriteString(" FOR ")
+	b.sb.WriteString(l.strength)
+	if l.wait != "" {
+		b.sb.WriteByte(' ')
+		b.sb.WriteString(l.wait)
+	}
+	return nil
+}
+
+func (s standardSQL) buildIndexHints(b *builder, hints []indexHint) error {
+	return errs.NewErrUnsupportedIndexHint(hints[0].typ + " INDEX")
+}
+
+func (s standardSQL) buildOptimizerHints(b *builder, hints []string) error {
+	return nil
+}
+
+func (s standardSQL) supportFullJoin() bool {
+	return true
+}
+
+func (s standardSQL) supportDeleteLimit() bool {
+	return false
+}
+
 type mysqlDialect struct {
 	standardSQL
 }
@@ -68,6 +163,87 @@ func (s mysqlDialect) buildUpsert(b *builder, upsert *Upsert) error {
 	return nil
 }
 
+// buildOrderBy MySQL 不支持 NULLS FIRST 和 NULLS LAST，
+// 所以先按照 expr IS NULL 排序来模拟
+func (s mysqlDialect) buildOrderBy(b *builder, ob OrderBy) error {
+	if ob.nulls != "" {
+		if err := b.buildExpression(ob.expr); err != nil {
+			return err
+		}
+		b.sb.WriteString(" IS NULL")
+		if ob.nulls == "FIRST" {
+			b.sb.WriteString(" DESC")
+		} else {
+			b.sb.WriteString(" ASC")
+		}
+		b.sb.WriteByte(',')
+	}
+	if err := b.buildExpression(ob.expr); err != nil {
+		return err
+	}
+	b.sb.WriteByte(' ')
+	b.sb.WriteString(ob.order)
+	return nil
+}
+
+// buildLimit MySQL 不支持单独使用 OFFSET，只有 OFFSET 的时候 LIMIT 取最大值
+func (s mysqlDialect) buildLimit(b *builder, limit int, offset int) error {
+	if limit <= 0 && offset > 0 {
+		b.sb.WriteString(" LIMIT 18446744073709551615 OFFSET ?")
+		b.addArgs(offset)
+		return nil
+	}
+	return s.standardSQL.buildLimit(b, limit, offset)
+}
+
+func (s mysqlDialect) buildFunc(b *builder, f FuncExpr) error {
+	switch f.name {
+	case funcConcat, funcIfNull, funcNow, funcDate:
+		return b.buildFuncCall(f.name, f.args)
+	case funcLength:
+		// MySQL 的 LENGTH 返回的是字节数
+		return b.buildFuncCall("CHAR_LENGTH", f.args)
+	default:
+		return s.standardSQL.buildFunc(b, f)
+	}
+}
+
+func (s mysqlDialect) buildIndexHints(b *builder, hints []indexHint) error {
+	for _, hint := range hints {
+		b.sb.WriteByte(' ')
+		b.sb.WriteString(hint.typ)
+		b.sb.WriteString(" INDEX (")
+		for i, idx := range hint.indexes {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			b.quote(idx)
+		}
+		b.sb.WriteByte(')')
+	}
+	return nil
+}
+
+func (s mysqlDialect) buildOptimizerHints(b *builder, hints []string) error {
+	b.sb.WriteString("/*+ ")
+	for i, hint := range hints {
+		if i > 0 {
+			b.sb.WriteByte(' ')
+		}
+		b.sb.WriteString(hint)
+	}
+	b.sb.WriteString(" */ ")
+	return nil
+}
+
+func (s mysqlDialect) supportFullJoin() bool {
+	return false
+}
+
+func (s mysqlDialect) supportDeleteLimit() bool {
+	return true
+}
+
 type sqliteDialect struct {
 	standardSQL
 }
@@ -118,6 +294,30 @@ func (s sqliteDialect) buildUpsert(b *builder, upsert *Upsert) error {
 	return nil
 }
 
+// buildLimit SQLite 不支持单独使用 OFFSET，LIMIT -1 表示不限制
+func (s sqliteDialect) buildLimit(b *builder, limit int, offset int) error {
+	if limit <= 0 && offset > 0 {
+		b.sb.WriteString(" LIMIT -1 OFFSET ?")
+		b.addArgs(offset)
+		return nil
+	}
+	return s.standardSQL.buildLimit(b, limit, offset)
+}
+
+func (s sqliteDialect) buildFunc(b *builder, f FuncExpr) error {
+	switch f.name {
+	case funcIfNull, funcLength, funcDate:
+		return b.buildFuncCall(f.name, f.args)
+	default:
+		return s.standardSQL.buildFunc(b, f)
+	}
+}
+
+// buildLock SQLite 锁的是整个数据库，不支持行锁
+func (s sqliteDialect) buildLock(b *builder, l rowLock) error {
+	return errs.NewErrUnsupportedLock("FOR " + l.strength)
+}
+
 type postgreDialect struct {
 	standardSQL
 }
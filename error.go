@@ -4,4 +4,7 @@ import "github.com/DaHuangQwQ/gsql/internal/errs"
 
 var (
 	ErrNoRows = errs.ErrNoRows
+
+	// ErrStop 在 Each 的回调里面返回，提前结束遍历，Each 本身不会返回错误
+	ErrStop = errs.ErrStop
 )
@@ -31,26 +31,108 @@ type binaryExpr struct {
 	left  Expression
 	op    op
 	right Expression
+	alias string
 }
 
 func (binaryExpr) expr() {}
 
+// MathExpr 算术表达式，例如 C("Age").Add(1)
 type MathExpr binaryExpr
 
-//func (m MathExpr) Add(val interface{}) MathExpr {
-//	return MathExpr{
-//		left:  m,
-//		op:    opAdd,
-//		right: valueOf(val),
-//	}
-//}
-//
-//func (m MathExpr) Multi(val interface{}) MathExpr {
-//	return MathExpr{
-//		left:  m,
-//		op:    opMulti,
-//		right: valueOf(val),
-//	}
-//}
+func (m MathExpr) Add(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opAdd,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Sub(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opSub,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Multi(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opMulti,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Div(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opDiv,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Mod(val any) MathExpr {
+	return MathExpr{
+		left:  m,
+		op:    opMod,
+		right: valueOf(val),
+	}
+}
+
+func (m MathExpr) Eq(arg any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opEQ,
+		right: valueOf(arg),
+	}
+}
+
+func (m MathExpr) Ne(arg any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opNE,
+		right: valueOf(arg),
+	}
+}
+
+func (m MathExpr) Lt(arg any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opLT,
+		right: valueOf(arg),
+	}
+}
+
+func (m MathExpr) Le(arg any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opLE,
+		right: valueOf(arg),
+	}
+}
+
+func (m MathExpr) Gt(arg any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opGT,
+		right: valueOf(arg),
+	}
+}
+
+func (m MathExpr) Ge(arg any) Predicate {
+	return Predicate{
+		left:  m,
+		op:    opGE,
+		right: valueOf(arg),
+	}
+}
+
+// As 在 SELECT 里面使用的别名
+func (m MathExpr) As(alias string) MathExpr {
+	m.alias = alias
+	return m
+}
 
 func (m MathExpr) expr() {}
+
+func (m MathExpr) selectable() {}
@@ -0,0 +1,216 @@
+package gsql
+
+const (
+	funcCoalesce = "COALESCE"
+	funcIfNull   = "IFNULL"
+	funcLower    = "LOWER"
+	funcUpper    = "UPPER"
+	funcConcat   = "CONCAT"
+	funcLength   = "LENGTH"
+	funcNow      = "NOW"
+	funcDate     = "DATE"
+	funcCast     = "CAST"
+)
+
+// FuncExpr SQL 函数调用，具体的 SQL 由 Dialect 决定
+type FuncExpr struct {
+	name string
+	args []Expression
+	// typ 是 CAST 的目标类型
+	typ   string
+	alias string
+}
+
+// Coalesce 返回第一个不为 NULL 的参数，参数可以是表达式，也可以是值
+func Coalesce(args ...any) FuncExpr {
+	return FuncExpr{
+		name: funcCoalesce,
+		args: valuesOf(args),
+	}
+}
+
+// IfNull expr 为 NULL 的时候返回 def
+func IfNull(expr Expression, def any) FuncExpr {
+	return FuncExpr{
+		name: funcIfNull,
+		args: []Expression{expr, valueOf(def)},
+	}
+}
+
+func Lower(expr Expression) FuncExpr {
+	return FuncExpr{
+		name: funcLower,
+		args: []Expression{expr},
+	}
+}
+
+func Upper(expr Expression) FuncExpr {
+	return FuncExpr{
+		name: funcUpper,
+		args: []Expression{expr},
+	}
+}
+
+// Concat 拼接字符串，参数可以是表达式，也可以是值
+func Concat(args ...any) FuncExpr {
+	return FuncExpr{
+		name: funcConcat,
+		args: valuesOf(args),
+	}
+}
+
+// Length 字符的个数
+func Length(expr Expression) FuncExpr {
+	return FuncExpr{
+		name: funcLength,
+		args: []Expression{expr},
+	}
+}
+
+// Now 当前时间
+func Now() FuncExpr {
+	return FuncExpr{
+		name: funcNow,
+	}
+}
+
+// Date 取日期部分
+func Date(expr Expression) FuncExpr {
+	return FuncExpr{
+		name: funcDate,
+		args: []Expression{expr},
+	}
+}
+
+// Cast 类型转换，typ 是数据库里面的类型，例如 CHAR、SIGNED、DATE
+func Cast(expr Expression, typ string) FuncExpr {
+	return FuncExpr{
+		name: funcCast,
+		args: []Expression{expr},
+		typ:  typ,
+	}
+}
+
+func valuesOf(args []any) []Expression {
+	res := make([]Expression, 0, len(args))
+	for _, arg := range args {
+		res = append(res, valueOf(arg))
+	}
+	return res
+}
+
+func (f FuncExpr) As(alias string) FuncExpr {
+	f.alias = alias
+	return f
+}
+
+func (f FuncExpr) Eq(arg any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opEQ,
+		right: valueOf(arg),
+	}
+}
+
+func (f FuncExpr) Ne(arg any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opNE,
+		right: valueOf(arg),
+	}
+}
+
+func (f FuncExpr) Lt(arg any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLT,
+		right: valueOf(arg),
+	}
+}
+
+func (f FuncExpr) Le(arg any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLE,
+		right: valueOf(arg),
+	}
+}
+
+func (f FuncExpr) Gt(arg any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opGT,
+		right: valueOf(arg),
+	}
+}
+
+func (f FuncExpr) Ge(arg any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opGE,
+		right: valueOf(arg),
+	}
+}
+
+func (f FuncExpr) Like(pattern any) Predicate {
+	return Predicate{
+		left:  f,
+		op:    opLike,
+		right: valueOf(pattern),
+	}
+}
+
+func (f FuncExpr) expr() {}
+
+func (f FuncExpr) selectable() {}
+
+func (b *builder) buildFunc(f FuncExpr) error {
+	if err := b.dialect.buildFunc(b, f); err != nil {
+		return err
+	}
+	if f.alias != "" {
+		b.sb.WriteString(" AS ")
+		b.quote(f.alias)
+	}
+	return nil
+}
+
+// buildFuncCall 构造 name(arg1,arg2) 形式的函数调用
+func (b *builder) buildFuncCall(name string, args []Expression) error {
+	b.sb.WriteString(name)
+	b.sb.WriteByte('(')
+	for i, arg := range args {
+		if i > 0 {
+			b.sb.WriteByte(',')
+		}
+		if err := b.buildExpression(arg); err != nil {
+			return err
+		}
+	}
+	b.sb.WriteByte(')')
+	return nil
+}
+
+// buildConcatOperator 使用 || 拼接字符串
+func (b *builder) buildConcatOperator(args []Expression) error {
+	for i, arg := range args {
+		if i > 0 {
+			b.sb.WriteString(" || ")
+		}
+		if err := b.buildExpression(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builder) buildCast(expr Expression, typ string) error {
+	b.sb.WriteString("CAST(")
+	if err := b.buildExpression(expr); err != nil {
+		return err
+	}
+	b.sb.WriteString(" AS ")
+	b.sb.WriteString(typ)
+	b.sb.WriteByte(')')
+	return nil
+}
@@ -0,0 +1,123 @@
+package gsql
+
+import (
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFuncExpr(t *testing.T) {
+	mysqlDB := memoryDB(t)
+	sqliteDB := memoryDB(t, WithDialect(DialectSQLite))
+	pgDB := memoryDB(t, WithDialect(DialectPostgreSQL))
+
+	testCases := []struct {
+		name      string
+		b         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "mysql select",
+			b: NewSelector[TestModel](mysqlDB).Select(
+				Concat(C("FirstName"), " ", C("LastName")).As("full_name"),
+				IfNull(C("LastName"), "").As("last_name"),
+				Length(C("FirstName")),
+				Now(),
+				Date(Now()).As("today"),
+				Cast(C("Age"), "CHAR")),
+			wantQuery: &Query{
+				SQL: "SELECT CONCAT(`first_name`,?,`last_name`) AS `full_name`,IFNULL(`last_name`,?) AS `last_name`," +
+					"CHAR_LENGTH(`first_name`),NOW(),DATE(NOW()) AS `today`,CAST(`age` AS CHAR) FROM `test_model`;",
+				Args: []any{" ", ""},
+			},
+		},
+		{
+			name: "sqlite select",
+			b: NewSelector[TestModel](sqliteDB).Select(
+				Concat(C("FirstName"), " ", C("LastName")).As("full_name"),
+				IfNull(C("LastName"), "").As("last_name"),
+				Length(C("FirstName")),
+				Now(),
+				Date(Now()).As("today"),
+				Cast(C("Age"), "TEXT")),
+			wantQuery: &Query{
+				SQL: "SELECT `first_name` || ? || `last_name` AS `full_name`,IFNULL(`last_name`,?) AS `last_name`," +
+					"LENGTH(`first_name`),CURRENT_TIMESTAMP,DATE(CURRENT_TIMESTAMP) AS `today`,CAST(`age` AS TEXT) FROM `test_model`;",
+				Args: []any{" ", ""},
+			},
+		},
+		{
+			name: "postgresql select",
+			b: NewSelector[TestModel](pgDB).Select(
+				Concat(C("FirstName"), C("LastName")),
+				IfNull(C("LastName"), ""),
+				Length(C("FirstName")),
+				Date(Now())),
+			wantQuery: &Query{
+				SQL: `SELECT "first_name" || "last_name",COALESCE("last_name",?),` +
+					`CHAR_LENGTH("first_name"),CAST(CURRENT_TIMESTAMP AS DATE) FROM "test_model";`,
+				Args: []any{""},
+			},
+		},
+		{
+			name: "coalesce lower upper",
+			b: NewSelector[TestModel](mysqlDB).Select(
+				Coalesce(C("LastName"), C("FirstName"), "unknown").As("name"),
+				Upper(C("FirstName"))).
+				Where(Lower(C("FirstName")).Like("da%")),
+			wantQuery: &Query{
+				SQL: "SELECT COALESCE(`last_name`,`first_name`,?) AS `name`,UPPER(`first_name`) FROM `test_model` " +
+					"WHERE LOWER(`first_name`) LIKE ?;",
+				Args: []any{"unknown", "da%"},
+			},
+		},
+		{
+			name: "predicates",
+			b: NewSelector[TestModel](mysqlDB).Where(
+				Length(C("FirstName")).Gt(3),
+				Date(C("FirstName")).Le(Date(Now())),
+				Coalesce(C("Age"), 0).Ne(0)),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE ((CHAR_LENGTH(`first_name`) > ?) " +
+					"AND (DATE(`first_name`) <= DATE(NOW()))) AND (COALESCE(`age`,?) != ?);",
+				Args: []any{3, 0, 0},
+			},
+		},
+		{
+			name: "order by alias",
+			b: NewSelector[TestModel](mysqlDB).Select(Length(C("FirstName")).As("len")).
+				OrderBy(Desc(C("len"))),
+			wantQuery: &Query{
+				SQL: "SELECT CHAR_LENGTH(`first_name`) AS `len` FROM `test_model` ORDER BY `len` DESC;",
+			},
+		},
+		{
+			name: "update",
+			b: NewUpdater[TestModel](sqliteDB).Set(
+				Assign("FirstName", Upper(C("FirstName"))),
+				Assign("LastName", Concat(C("LastName"), "!"))).
+				Where(C("Id").Eq(1)),
+			wantQuery: &Query{
+				SQL:  "UPDATE `test_model` SET `first_name`=UPPER(`first_name`),`last_name`=`last_name` || ? WHERE `id` = ?;",
+				Args: []any{"!", 1},
+			},
+		},
+		{
+			name:    "invalid column",
+			b:       NewSelector[TestModel](mysqlDB).Select(Upper(C("Invalid"))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.b.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
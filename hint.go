@@ -0,0 +1,40 @@
+package gsql
+
+// indexHint MySQL 的索引提示，例如 FORCE INDEX (`idx_user`)
+type indexHint struct {
+	// typ 是 USE、FORCE 或者 IGNORE
+	typ     string
+	indexes []string
+}
+
+// UseIndex USE INDEX (indexes)
+func (t Table) UseIndex(indexes ...string) Table {
+	return t.withHint("USE", indexes)
+}
+
+// ForceIndex FORCE INDEX (indexes)
+func (t Table) ForceIndex(indexes ...string) Table {
+	return t.withHint("FORCE", indexes)
+}
+
+// IgnoreIndex IGNORE INDEX (indexes)
+func (t Table) IgnoreIndex(indexes ...string) Table {
+	return t.withHint("IGNORE", indexes)
+}
+
+func (t Table) withHint(typ string, indexes []string) Table {
+	hints := make([]indexHint, 0, len(t.hints)+1)
+	hints = append(hints, t.hints...)
+	t.hints = append(hints, indexHint{
+		typ:     typ,
+		indexes: indexes,
+	})
+	return t
+}
+
+// OptimizerHint 优化器提示，例如 OptimizerHint("MAX_EXECUTION_TIME(1000)")，
+// 生成 SELECT /*+ MAX_EXECUTION_TIME(1000) */ ...，不支持的数据库会忽略
+func (s *Selector[T]) OptimizerHint(hints ...string) *Selector[T] {
+	s.hints = append(s.hints, hints...)
+	return s
+}
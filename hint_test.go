@@ -0,0 +1,93 @@
+package gsql
+
+import (
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelector_Hint(t *testing.T) {
+	mysqlDB := memoryDB(t)
+	sqliteDB := memoryDB(t, WithDialect(DialectSQLite))
+	type Order struct {
+		Id     int64
+		UserId int64
+	}
+
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "force index",
+			s: NewSelector[Order](mysqlDB).From(TableOf(&Order{}).ForceIndex("idx_user")).
+				Where(C("UserId").Eq(1)),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `order` FORCE INDEX (`idx_user`) WHERE `user_id` = ?;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "multiple hints with alias",
+			s: NewSelector[Order](mysqlDB).
+				From(TableOf(&Order{}).UseIndex("idx_user", "idx_created").IgnoreIndex("PRIMARY").As("o")),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `order` AS `o` USE INDEX (`idx_user`,`idx_created`) IGNORE INDEX (`PRIMARY`);",
+			},
+		},
+		{
+			name: "join",
+			s: func() QueryBuilder {
+				o := TableOf(&Order{}).As("o").ForceIndex("idx_user")
+				u := TableOf(&TestModel{}).As("u")
+				return NewSelector[Order](mysqlDB).From(o.Join(u).On(o.C("UserId").Eq(u.C("Id"))))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM (`order` AS `o` FORCE INDEX (`idx_user`) JOIN `test_model` AS `u` ON `o`.`user_id` = `u`.`id`);",
+			},
+		},
+		{
+			name: "optimizer hint",
+			s: NewSelector[Order](mysqlDB).OptimizerHint("MAX_EXECUTION_TIME(1000)", "NO_INDEX_MERGE(order)").
+				Distinct().Select(C("UserId")),
+			wantQuery: &Query{
+				SQL: "SELECT /*+ MAX_EXECUTION_TIME(1000) NO_INDEX_MERGE(order) */ DISTINCT `user_id` FROM `order`;",
+			},
+		},
+		{
+			name: "sqlite optimizer hint",
+			s:    NewSelector[Order](sqliteDB).OptimizerHint("MAX_EXECUTION_TIME(1000)"),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `order`;",
+			},
+		},
+		{
+			name:    "sqlite index hint",
+			s:       NewSelector[Order](sqliteDB).From(TableOf(&Order{}).ForceIndex("idx_user")),
+			wantErr: errs.NewErrUnsupportedIndexHint("FORCE INDEX"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestTable_Hint(t *testing.T) {
+	// 添加提示不能修改原来的表
+	base := TableOf(&TestModel{}).UseIndex("idx_a")
+	t1 := base.ForceIndex("idx_b")
+	t2 := base.IgnoreIndex("idx_c")
+	assert.Len(t, base.hints, 1)
+	assert.Equal(t, "FORCE", t1.hints[1].typ)
+	assert.Equal(t, "IGNORE", t2.hints[1].typ)
+}
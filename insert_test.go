@@ -178,6 +178,18 @@ func TestInserter_Build(t *testing.T) {
 					int64(13), "DaMing", &sql.NullString{String: "Deng", Valid: true}, int8(19)},
 			},
 		},
+		{
+			name: "upsert-update math expression",
+			i: NewInserter[TestModel](db).Values(&TestModel{
+				Id:  12,
+				Age: 18,
+			}).OnDuplicateKey().Update(Assign("Age", C("Age").Add(1))),
+			wantRes: &Query{
+				SQL: "INSERT INTO `test_model`(`id`,`first_name`,`last_name`,`age`) VALUES (?,?,?,?) " +
+					"ON DUPLICATE KEY UPDATE `age`=`age` + ?;",
+				Args: []any{int64(12), "", (*sql.NullString)(nil), int8(18), 1},
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -14,7 +14,29 @@ var (
 
 	ErrNoRows = errors.New("no rows in result set")
 
+	// ErrStop 提前结束遍历
+	ErrStop = errors.New("stop iteration")
+
 	ErrInsertZeroRow = errors.New("no values to insert")
+
+	ErrNoUpdatedColumns = errors.New("no columns to update")
+	ErrNoUpdatedEntity  = errors.New("no entity to update")
+
+	// ErrEmptyCase CASE 表达式至少需要一个 WHEN
+	ErrEmptyCase = errors.New("case expression without when")
+
+	// ErrLockWithoutTx 要求在事务里面加锁，但是不在事务里面
+	ErrLockWithoutTx = errors.New("lock clause requires a transaction")
+	// ErrLockModifierWithoutLock 使用了 NOWAIT 或者 SKIP LOCKED，但是没有 FOR UPDATE 或者 FOR SHARE
+	ErrLockModifierWithoutLock = errors.New("lock modifier requires FOR UPDATE or FOR SHARE")
+
+	// ErrNoCursorKeys 游标分页至少需要一个排序的列
+	ErrNoCursorKeys = errors.New("cursor pagination requires order columns")
+
+	// ErrMultipleFullJoin 使用 UNION 模拟 FULL OUTER JOIN 的时候只支持一个
+	ErrMultipleFullJoin = errors.New("only one full outer join can be emulated")
+	// ErrFullJoinWithoutLeftColumn 模拟 FULL OUTER JOIN 需要在 ON 里面用到左边的列
+	ErrFullJoinWithoutLeftColumn = errors.New("emulated full outer join requires a left column in the join condition")
 )
 
 func NewErrUnknownField(name any) error {
@@ -25,6 +47,10 @@ func NewErrUnknownColumn(name any) error {
 	return fmt.Errorf("gsql: unknown column: %v", name)
 }
 
+func NewErrDuplicateColumn(name any) error {
+	return fmt.Errorf("gsql: duplicate column: %v", name)
+}
+
 func NewErrInvalidTagContent(name any) error {
 	return fmt.Errorf("gsql: invalid tag content: %v", name)
 }
@@ -37,6 +63,18 @@ func NewErrUnsupportedTable(table any) error {
 	return fmt.Errorf("gsql: unsupported table: %v", table)
 }
 
+func NewErrUnsupportedLock(clause string) error {
+	return fmt.Errorf("gsql: unsupported lock clause: %s", clause)
+}
+
+func NewErrUnsupportedClause(clause string) error {
+	return fmt.Errorf("gsql: unsupported clause: %s", clause)
+}
+
+func NewErrUnsupportedIndexHint(hint string) error {
+	return fmt.Errorf("gsql: unsupported index hint: %s", hint)
+}
+
 func NewErrUnsupportedAssignable(assign any) error {
 	return fmt.Errorf("gsql: unsupported assignable: %v", assign)
 }
@@ -44,3 +82,23 @@ func NewErrUnsupportedAssignable(assign any) error {
 func NewErrFailedToRollbackTx(bizErr error, rbErr error, panicked bool) error {
 	return fmt.Errorf("gsql: failed to rollback transaction bizErr:%w, rbErr:%s , isPanic:%t ", bizErr, rbErr, panicked)
 }
+
+func NewErrInvalidBatchSize(size int) error {
+	return fmt.Errorf("gsql: invalid batch size: %d", size)
+}
+
+func NewErrInvalidPageSize(size int) error {
+	return fmt.Errorf("gsql: invalid page size: %d", size)
+}
+
+func NewErrInvalidCursor(cursor string) error {
+	return fmt.Errorf("gsql: invalid cursor: %s", cursor)
+}
+
+func NewErrInvalidCompoundPart(clause string) error {
+	return fmt.Errorf("gsql: compound query part can not have %s", clause)
+}
+
+func NewErrColumnCountMismatch(want int, got int) error {
+	return fmt.Errorf("gsql: column count mismatch, want %d, got %d", want, got)
+}
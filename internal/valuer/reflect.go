@@ -5,6 +5,7 @@ import (
 	"github.com/DaHuangQwQ/gsql/internal/errs"
 	gsql "github.com/DaHuangQwQ/gsql/model"
 	"reflect"
+	"strings"
 )
 
 var _ Creator = NewReflectValue
@@ -53,7 +54,7 @@ func (r reflectValuer) SetColumns(rows *sql.Rows) error {
 		if !ok {
 			return errs.NewErrUnknownColumn(c)
 		}
-		tpValueElem.FieldByName(fd.GoName).
+		fieldByName(tpValueElem, fd.GoName).
 			Set(valElems[i])
 	}
 
@@ -61,7 +62,19 @@ func (r reflectValuer) SetColumns(rows *sql.Rows) error {
 }
 
 func (r reflectValuer) Field(name string) (any, error) {
-	val := r.val.FieldByName(name)
+	fd, ok := r.model.FieldMap[name]
+	if !ok {
+		return nil, errs.NewErrUnknownField(name)
+	}
+	val := fieldByName(r.val, fd.GoName)
 
 	return val.Interface(), nil
 }
+
+// fieldByName 组合结构体展开后的字段名是完整的路径，例如 User.Id
+func fieldByName(val reflect.Value, name string) reflect.Value {
+	for _, seg := range strings.Split(name, ".") {
+		val = val.FieldByName(seg)
+	}
+	return val
+}
@@ -70,6 +70,35 @@ func testSetColumns(t *testing.T, creator Creator) {
 				LastName: &sql.NullString{Valid: true, String: "Jerry"},
 			},
 		},
+		{
+			// 组合结构体，列按照前缀映射到不同的子结构体
+			name:   "embed",
+			entity: &TestCompositeModel{},
+			rows: func() *sqlmock.Rows {
+				rows := sqlmock.NewRows([]string{"id", "first_name", "u_id", "u_name"})
+				rows.AddRow("1", "Tom", "2", "Jerry")
+				return rows
+			},
+			wantEntity: &TestCompositeModel{
+				TestModel: TestModel{Id: 1, FirstName: "Tom"},
+				TestUser:  TestUser{Id: 2, Name: "Jerry"},
+			},
+		},
+		{
+			// 匿名嵌入公共字段的结构体，列没有前缀
+			name:   "embed shared struct",
+			entity: &TestBaseModel{},
+			rows: func() *sqlmock.Rows {
+				rows := sqlmock.NewRows([]string{"id", "created_at", "name"})
+				rows.AddRow("1", "100", "Tom")
+				return rows
+			},
+			wantEntity: &TestBaseModel{
+				TestBase: TestBase{CreatedAt: 100},
+				Id:       1,
+				Name:     "Tom",
+			},
+		},
 	}
 
 	r := gsql.NewRegistry()
@@ -108,3 +137,23 @@ type TestModel struct {
 	Age       int8
 	LastName  *sql.NullString
 }
+
+type TestUser struct {
+	Id   int64
+	Name string
+}
+
+type TestCompositeModel struct {
+	TestModel
+	TestUser `orm:"prefix=u_"`
+}
+
+type TestBase struct {
+	CreatedAt int64
+}
+
+type TestBaseModel struct {
+	TestBase
+	Id   int64
+	Name string
+}
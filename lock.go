@@ -0,0 +1,58 @@
+package gsql
+
+import "github.com/DaHuangQwQ/gsql/internal/errs"
+
+// rowLock 行锁子句，例如 FOR UPDATE SKIP LOCKED
+type rowLock struct {
+	// strength 是 UPDATE 或者 SHARE
+	strength string
+	// wait 是 NOWAIT 或者 SKIP LOCKED，为空表示等待锁
+	wait string
+	// requireTx 为 true 的时候，只允许在事务里面加锁
+	requireTx bool
+}
+
+// ForUpdate SELECT ... FOR UPDATE
+func (s *Selector[T]) ForUpdate() *Selector[T] {
+	s.lock.strength = "UPDATE"
+	return s
+}
+
+// ForShare SELECT ... FOR SHARE
+func (s *Selector[T]) ForShare() *Selector[T] {
+	s.lock.strength = "SHARE"
+	return s
+}
+
+// NoWait 拿不到锁的时候立刻返回错误，需要和 ForUpdate 或者 ForShare 一起使用
+func (s *Selector[T]) NoWait() *Selector[T] {
+	s.lock.wait = "NOWAIT"
+	return s
+}
+
+// SkipLocked 跳过已经被锁住的行，需要和 ForUpdate 或者 ForShare 一起使用
+func (s *Selector[T]) SkipLocked() *Selector[T] {
+	s.lock.wait = "SKIP LOCKED"
+	return s
+}
+
+// RequireTx 加锁的查询只能在事务里面执行，否则锁在语句结束的时候就释放了
+func (s *Selector[T]) RequireTx() *Selector[T] {
+	s.lock.requireTx = true
+	return s
+}
+
+func (s *Selector[T]) buildLock() error {
+	if s.lock.strength == "" {
+		if s.lock.wait != "" {
+			return errs.ErrLockModifierWithoutLock
+		}
+		return nil
+	}
+	if s.lock.requireTx {
+		if _, ok := s.session.(*Tx); !ok {
+			return errs.ErrLockWithoutTx
+		}
+	}
+	return s.dialect.buildLock(&s.builder, s.lock)
+}
@@ -0,0 +1,106 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSelector_Lock(t *testing.T) {
+	mysqlDB := memoryDB(t)
+	sqliteDB := memoryDB(t, WithDialect(DialectSQLite))
+	pgDB := memoryDB(t, WithDialect(DialectPostgreSQL))
+
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "for update",
+			s:    NewSelector[TestModel](mysqlDB).Where(C("Id").Eq(1)).ForUpdate(),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` = ? FOR UPDATE;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "for share",
+			s:    NewSelector[TestModel](mysqlDB).Where(C("Id").Eq(1)).ForShare(),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` = ? FOR SHARE;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "for update skip locked",
+			s: NewSelector[TestModel](mysqlDB).Where(C("Age").Eq(0)).
+				OrderBy(Asc(C("Id"))).Limit(10).ForUpdate().SkipLocked(),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `age` = ? ORDER BY `id` ASC LIMIT ? FOR UPDATE SKIP LOCKED;",
+				Args: []any{0, 10},
+			},
+		},
+		{
+			name: "postgresql for share nowait",
+			s:    NewSelector[TestModel](pgDB).Where(C("Id").Eq(1)).ForShare().NoWait(),
+			wantQuery: &Query{
+				SQL:  `SELECT * FROM "test_model" WHERE "id" = ? FOR SHARE NOWAIT;`,
+				Args: []any{1},
+			},
+		},
+		{
+			name:    "sqlite",
+			s:       NewSelector[TestModel](sqliteDB).Where(C("Id").Eq(1)).ForUpdate(),
+			wantErr: errs.NewErrUnsupportedLock("FOR UPDATE"),
+		},
+		{
+			name:    "modifier without lock",
+			s:       NewSelector[TestModel](mysqlDB).NoWait(),
+			wantErr: errs.ErrLockModifierWithoutLock,
+		},
+		{
+			name:    "require tx",
+			s:       NewSelector[TestModel](mysqlDB).ForUpdate().RequireTx(),
+			wantErr: errs.ErrLockWithoutTx,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_Lock_Tx(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	rows := sqlmock.NewRows([]string{"id", "first_name", "last_name", "age"})
+	rows.AddRow("1", "da", "huang", "18")
+	mock.ExpectQuery("SELECT \\* FROM `test_model` WHERE `id` = \\? FOR UPDATE NOWAIT;").
+		WithArgs(1).WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	res, err := NewSelector[TestModel](tx).Where(C("Id").Eq(1)).
+		ForUpdate().NoWait().RequireTx().Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.Id)
+	require.NoError(t, tx.Commit())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,131 @@
+package gsql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/DaHuangQwQ/gsql/model"
+	"strconv"
+	"strings"
+)
+
+// GetMaps 把结果集的每一行转成列名到值的映射，适合没有模型的查询。
+// 结果集里面有同名的列的时候返回错误，例如 JOIN 之后 SELECT *
+func (s *Selector[T]) GetMaps(ctx context.Context) ([]map[string]any, error) {
+	res := getMaps(ctx, s.session, s.core, &QueryContext{
+		Type:    TypeSelect,
+		Builder: s,
+		Model:   s.model,
+	})
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return res.Result.([]map[string]any), nil
+}
+
+// GetMaps 把结果集的每一行转成列名到值的映射。
+// T 可以不是一个合法的模型，例如 RawQuery[any]
+func (r *RawQuerier[T]) GetMaps(ctx context.Context) ([]map[string]any, error) {
+	m, err := r.r.Get(new(T))
+	if err != nil {
+		m = &model.Model{}
+	}
+	res := getMaps(ctx, r.session, r.core, &QueryContext{
+		Type:    TypeRaw,
+		Builder: r,
+		Model:   m,
+	})
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return res.Result.([]map[string]any), nil
+}
+
+func scanMaps(rows *sql.Rows) ([]map[string]any, error) {
+	cts, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	// 例如 JOIN 之后 SELECT *，同名的列放进 map 里面会互相覆盖，需要使用别名区分
+	names := make(map[string]struct{}, len(cts))
+	for _, ct := range cts {
+		if _, ok := names[ct.Name()]; ok {
+			return nil, errs.NewErrDuplicateColumn(ct.Name())
+		}
+		names[ct.Name()] = struct{}{}
+	}
+
+	res := make([]map[string]any, 0, 8)
+	vals := make([]any, len(cts))
+	ptrs := make([]any, len(cts))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(cts))
+		for i, ct := range cts {
+			row[ct.Name()], err = convertValue(ct.DatabaseTypeName(), vals[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// convertValue 驱动返回 []byte 或者 string 的时候，按照列的类型转成对应的 Go 类型。
+// DECIMAL 转成 float64 会丢失精度，所以保留字符串
+func convertValue(typ string, val any) (any, error) {
+	var str string
+	switch v := val.(type) {
+	case []byte:
+		if isBinaryType(typ) {
+			// 驱动会复用 []byte，所以需要复制一份
+			return append(make([]byte, 0, len(v)), v...), nil
+		}
+		str = string(v)
+	case string:
+		str = v
+	default:
+		return val, nil
+	}
+
+	typ = strings.ToUpper(typ)
+	switch {
+	case strings.HasPrefix(typ, "UNSIGNED") && isIntType(strings.TrimSpace(strings.TrimPrefix(typ, "UNSIGNED"))):
+		return strconv.ParseUint(str, 10, 64)
+	case isIntType(typ):
+		return strconv.ParseInt(str, 10, 64)
+	case typ == "FLOAT", typ == "DOUBLE", typ == "REAL":
+		return strconv.ParseFloat(str, 64)
+	case typ == "BOOL", typ == "BOOLEAN":
+		return strconv.ParseBool(str)
+	default:
+		return str, nil
+	}
+}
+
+func isIntType(typ string) bool {
+	switch typ {
+	case "INT", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT", "INT2", "INT4", "INT8", "YEAR":
+		return true
+	default:
+		return false
+	}
+}
+
+func isBinaryType(typ string) bool {
+	switch strings.ToUpper(typ) {
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "BYTEA":
+		return true
+	default:
+		return false
+	}
+}
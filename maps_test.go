@@ -0,0 +1,81 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSelector_GetMaps(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	rows := mock.NewRowsWithColumnDefinition(
+		mock.NewColumn("id").OfType("BIGINT", int64(0)),
+		mock.NewColumn("cnt").OfType("UNSIGNED BIGINT", uint64(0)),
+		mock.NewColumn("name").OfType("VARCHAR", ""),
+		mock.NewColumn("score").OfType("DOUBLE", float64(0)),
+		mock.NewColumn("price").OfType("DECIMAL", ""),
+		mock.NewColumn("data").OfType("BLOB", []byte(nil)),
+		mock.NewColumn("extra").OfType("VARCHAR", ""),
+	)
+	rows.AddRow([]byte("1"), []byte("2"), []byte("Tom"), []byte("1.5"), []byte("9.99"), []byte("abc"), nil)
+	rows.AddRow(int64(3), "4", "Jerry", 2.5, "0.10", []byte{}, "x")
+	mock.ExpectQuery("SELECT .*").WillReturnRows(rows)
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	res, err := NewSelector[TestModel](db).GetMaps(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{
+			"id":    int64(1),
+			"cnt":   uint64(2),
+			"name":  "Tom",
+			"score": 1.5,
+			"price": "9.99",
+			"data":  []byte("abc"),
+			"extra": nil,
+		},
+		{
+			"id":    int64(3),
+			"cnt":   uint64(4),
+			"name":  "Jerry",
+			"score": 2.5,
+			"price": "0.10",
+			"data":  []byte{},
+			"extra": "x",
+		},
+	}, res)
+
+	res, err = NewSelector[TestModel](db).Where(C("Id").Eq(100)).GetMaps(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{}, res)
+}
+
+func TestRawQuerier_GetMaps(t *testing.T) {
+	db := memoryDB(t, WithDialect(DialectSQLite))
+	ctx := context.Background()
+	// 建表和写数据借用 TestModel，查询的时候不需要模型
+	require.NoError(t, RawQuery[TestModel](db, "CREATE TABLE IF NOT EXISTS `report_item`("+
+		"`id` INTEGER PRIMARY KEY, `name` TEXT, `price` REAL)").Exec(ctx).Err())
+	require.NoError(t, RawQuery[TestModel](db, "DELETE FROM `report_item`").Exec(ctx).Err())
+	require.NoError(t, RawQuery[TestModel](db, "INSERT INTO `report_item` VALUES (1, 'pen', 1.5), (2, 'book', 12)").Exec(ctx).Err())
+
+	res, err := RawQuery[any](db, "SELECT `id`,`name`,`price`,COUNT(*) OVER () AS `total` "+
+		"FROM `report_item` WHERE `id` > ? ORDER BY `id`", 0).GetMaps(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(1), "name": "pen", "price": 1.5, "total": int64(2)},
+		{"id": int64(2), "name": "book", "price": 12.0, "total": int64(2)},
+	}, res)
+
+	// 同名的列会互相覆盖，需要使用别名区分
+	_, err = RawQuery[any](db, "SELECT `a`.`id`,`b`.`id` FROM `report_item` AS `a` "+
+		"JOIN `report_item` AS `b` ON `a`.`id` = `b`.`id`").GetMaps(ctx)
+	assert.Equal(t, errs.NewErrDuplicateColumn("id"), err)
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"database/sql"
 	"github.com/DaHuangQwQ/gsql/internal/errs"
 	"reflect"
 	"regexp"
@@ -10,6 +11,8 @@ import (
 
 const (
 	tagKeyColumn = "column"
+	// tagKeyPrefix 组合结构体里面子结构体的列名前缀
+	tagKeyPrefix = "prefix"
 )
 
 type Model struct {
@@ -20,6 +23,17 @@ type Model struct {
 	FieldMap map[string]*Field
 	// ColumnMap 列名到字段定义的映射
 	ColumnMap map[string]*Field
+
+	// Embeds 组合结构体里面展开的子结构体，JOIN 的结果按照它们映射到不同的表
+	Embeds []*Embed
+}
+
+// Embed 组合结构体里面展开的子结构体
+type Embed struct {
+	GoName string
+	Typ    reflect.Type
+	// Prefix 子结构体的列在结果集里面的前缀
+	Prefix string
 }
 
 type Field struct {
@@ -29,6 +43,8 @@ type Field struct {
 	Offset  uintptr
 }
 
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
 // registry 元数据的注册中心
 type registry struct {
 	models sync.Map
@@ -66,33 +82,13 @@ func (r *registry) Register(entity any, opts ...ModelOption) (*Model, error) {
 		return nil, errs.ErrInvalidType
 	}
 
-	numFields := tye.NumField()
-
-	fieldMap := make(map[string]*Field, numFields)
-	columnMap := make(map[string]*Field, numFields)
-	fields := make([]*Field, 0, numFields)
-
-	for i := 0; i < numFields; i++ {
-		fd := tye.Field(i)
-		tags, err := r.parseTag(fd.Tag)
-		if err != nil {
-			return nil, err
-		}
-		colName, _ := tags[tagKeyColumn]
-		if colName == "" {
-			colName = fd.Name
-		}
-
-		fdMeta := &Field{
-			GoName:  fd.Name,
-			ColName: underscoreName(colName),
-			Typ:     fd.Type,
-			Offset:  fd.Offset,
-		}
-
-		fieldMap[fd.Name] = fdMeta
-		columnMap[underscoreName(colName)] = fdMeta
-		fields = append(fields, fdMeta)
+	res := &Model{
+		Fields:    make([]*Field, 0, tye.NumField()),
+		FieldMap:  make(map[string]*Field, tye.NumField()),
+		ColumnMap: make(map[string]*Field, tye.NumField()),
+	}
+	if err := r.parseFields(res, tye, "", "", 0); err != nil {
+		return nil, err
 	}
 
 	tableName := ""
@@ -103,12 +99,7 @@ func (r *registry) Register(entity any, opts ...ModelOption) (*Model, error) {
 		tableName = underscoreName(tye.Name())
 	}
 
-	res := &Model{
-		TableName: tableName,
-		Fields:    fields,
-		FieldMap:  fieldMap,
-		ColumnMap: columnMap,
-	}
+	res.TableName = tableName
 
 	for _, opt := range opts {
 		err := opt(res)
@@ -120,6 +111,78 @@ func (r *registry) Register(entity any, opts ...ModelOption) (*Model, error) {
 	return res, nil
 }
 
+// parseFields 解析结构体的字段。
+// 匿名嵌入的结构体和带有 prefix 标签的结构体字段会被展开，
+// 它们的字段名是完整的路径，例如 User.Id，偏移量是相对于最外层结构体的
+func (r *registry) parseFields(m *Model, typ reflect.Type, goPrefix string, colPrefix string, offset uintptr) error {
+	for i := 0; i < typ.NumField(); i++ {
+		fd := typ.Field(i)
+		tags, err := r.parseTag(fd.Tag)
+		if err != nil {
+			return err
+		}
+		goName := goPrefix + fd.Name
+
+		prefix, hasPrefix := tags[tagKeyPrefix]
+		if hasPrefix || isEmbedded(fd) {
+			if fd.Type.Kind() != reflect.Struct {
+				return errs.NewErrInvalidTagContent(tagKeyPrefix + "=" + prefix)
+			}
+			embed := &Embed{
+				GoName: goName,
+				Typ:    fd.Type,
+				Prefix: colPrefix + prefix,
+			}
+			m.Embeds = append(m.Embeds, embed)
+			before := len(m.Fields)
+			err = r.parseFields(m, fd.Type, goName+".", embed.Prefix, offset+fd.Offset)
+			if err != nil {
+				return err
+			}
+			// 匿名嵌入的字段可以直接使用字段名，和 Go 的字段提升一样
+			if fd.Anonymous {
+				for _, sub := range m.Fields[before:] {
+					name := strings.TrimPrefix(sub.GoName, goName+".")
+					if _, ok := m.FieldMap[name]; !ok {
+						m.FieldMap[name] = sub
+					}
+				}
+			}
+			continue
+		}
+
+		colName, _ := tags[tagKeyColumn]
+		if colName == "" {
+			colName = fd.Name
+		}
+		colName = colPrefix + underscoreName(colName)
+		if _, ok := m.ColumnMap[colName]; ok {
+			return errs.NewErrDuplicateColumn(colName)
+		}
+
+		fdMeta := &Field{
+			GoName:  goName,
+			ColName: colName,
+			Typ:     fd.Type,
+			Offset:  offset + fd.Offset,
+		}
+
+		m.FieldMap[goName] = fdMeta
+		m.ColumnMap[colName] = fdMeta
+		m.Fields = append(m.Fields, fdMeta)
+	}
+	return nil
+}
+
+// isEmbedded 匿名嵌入的结构体需要展开，
+// 但是实现了 sql.Scanner 的类型，例如 sql.NullString，本身就是一个列
+func isEmbedded(fd reflect.StructField) bool {
+	if !fd.Anonymous || fd.Type.Kind() != reflect.Struct {
+		return false
+	}
+	return !reflect.PointerTo(fd.Type).Implements(scannerType)
+}
+
 func (r *registry) parseTag(tag reflect.StructTag) (map[string]string, error) {
 	ormTag, ok := tag.Lookup("orm")
 	if !ok {
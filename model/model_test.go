@@ -71,6 +71,134 @@ func Test_registry_Register(t *testing.T) {
 	}
 }
 
+func TestRegistry_Register_Embed(t *testing.T) {
+	type User struct {
+		Id   int64
+		Name string
+	}
+	type Order struct {
+		Id     int64
+		UserId int64
+	}
+	type Base struct {
+		CreatedAt int64
+		UpdatedAt int64
+	}
+	testCases := []struct {
+		name       string
+		entity     any
+		wantFields []*Field
+		wantEmbeds []*Embed
+		// wantPromoted 匿名嵌入提升上来的字段名
+		wantPromoted map[string]string
+		wantErr      error
+	}{
+		{
+			name: "embed with prefix",
+			entity: &struct {
+				Order
+				User `orm:"prefix=u_"`
+			}{},
+			wantFields: []*Field{
+				{GoName: "Order.Id", ColName: "id", Typ: reflect.TypeOf(int64(0))},
+				{GoName: "Order.UserId", ColName: "user_id", Typ: reflect.TypeOf(int64(0)), Offset: 8},
+				{GoName: "User.Id", ColName: "u_id", Typ: reflect.TypeOf(int64(0)), Offset: 16},
+				{GoName: "User.Name", ColName: "u_name", Typ: reflect.TypeOf(""), Offset: 24},
+			},
+			wantEmbeds: []*Embed{
+				{GoName: "Order", Typ: reflect.TypeOf(Order{})},
+				{GoName: "User", Typ: reflect.TypeOf(User{}), Prefix: "u_"},
+			},
+			wantPromoted: map[string]string{
+				"Id":     "Order.Id",
+				"UserId": "Order.UserId",
+				"Name":   "User.Name",
+			},
+		},
+		{
+			name: "named field with prefix",
+			entity: &struct {
+				Amount int64
+				Buyer  User `orm:"prefix=buyer_"`
+			}{},
+			wantFields: []*Field{
+				{GoName: "Amount", ColName: "amount", Typ: reflect.TypeOf(int64(0))},
+				{GoName: "Buyer.Id", ColName: "buyer_id", Typ: reflect.TypeOf(int64(0)), Offset: 8},
+				{GoName: "Buyer.Name", ColName: "buyer_name", Typ: reflect.TypeOf(""), Offset: 16},
+			},
+			wantEmbeds: []*Embed{
+				{GoName: "Buyer", Typ: reflect.TypeOf(User{}), Prefix: "buyer_"},
+			},
+		},
+		{
+			// 公共的字段放在一个结构体里面，不是一张表
+			name: "embed shared struct",
+			entity: &struct {
+				Base
+				Id     int64
+				UserId int64
+			}{},
+			wantFields: []*Field{
+				{GoName: "Base.CreatedAt", ColName: "created_at", Typ: reflect.TypeOf(int64(0))},
+				{GoName: "Base.UpdatedAt", ColName: "updated_at", Typ: reflect.TypeOf(int64(0)), Offset: 8},
+				{GoName: "Id", ColName: "id", Typ: reflect.TypeOf(int64(0)), Offset: 16},
+				{GoName: "UserId", ColName: "user_id", Typ: reflect.TypeOf(int64(0)), Offset: 24},
+			},
+			wantEmbeds: []*Embed{
+				{GoName: "Base", Typ: reflect.TypeOf(Base{})},
+			},
+			wantPromoted: map[string]string{
+				"CreatedAt": "Base.CreatedAt",
+				"UpdatedAt": "Base.UpdatedAt",
+			},
+		},
+		{
+			name: "scanner not embedded",
+			entity: &struct {
+				sql.NullString
+			}{},
+			wantFields: []*Field{
+				{GoName: "NullString", ColName: "null_string", Typ: reflect.TypeOf(sql.NullString{})},
+			},
+		},
+		{
+			name: "duplicate column",
+			entity: &struct {
+				Order
+				User
+			}{},
+			wantErr: errs.NewErrDuplicateColumn("id"),
+		},
+		{
+			name: "prefix on non struct",
+			entity: &struct {
+				Name string `orm:"prefix=n_"`
+			}{},
+			wantErr: errs.NewErrInvalidTagContent("prefix=n_"),
+		},
+	}
+
+	r := NewRegistry()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := r.Register(tc.entity)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantFields, m.Fields)
+			assert.Equal(t, tc.wantEmbeds, m.Embeds)
+			for _, f := range tc.wantFields {
+				assert.Equal(t, f, m.FieldMap[f.GoName])
+				assert.Equal(t, f, m.ColumnMap[f.ColName])
+			}
+			for name, goName := range tc.wantPromoted {
+				assert.Equal(t, m.FieldMap[goName], m.FieldMap[name])
+			}
+		})
+	}
+}
+
 func TestRegistry_get(t *testing.T) {
 	testCases := []struct {
 		name string
@@ -0,0 +1,37 @@
+package gsql
+
+// OrderBy 排序条件
+type OrderBy struct {
+	expr  Expression
+	order string
+	// nulls 是 NULLS FIRST 或者 NULLS LAST 里面的 FIRST 和 LAST
+	nulls string
+}
+
+// Asc 升序，expr 可以是列、聚合函数或者原生表达式
+func Asc(expr Expression) OrderBy {
+	return OrderBy{
+		expr:  expr,
+		order: "ASC",
+	}
+}
+
+// Desc 降序，expr 可以是列、聚合函数或者原生表达式
+func Desc(expr Expression) OrderBy {
+	return OrderBy{
+		expr:  expr,
+		order: "DESC",
+	}
+}
+
+// NullsFirst NULL 排在最前面，不支持的方言会模拟实现
+func (o OrderBy) NullsFirst() OrderBy {
+	o.nulls = "FIRST"
+	return o
+}
+
+// NullsLast NULL 排在最后面，不支持的方言会模拟实现
+func (o OrderBy) NullsLast() OrderBy {
+	o.nulls = "LAST"
+	return o
+}
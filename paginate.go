@@ -0,0 +1,230 @@
+package gsql
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/DaHuangQwQ/gsql/model"
+	"reflect"
+)
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// Paginator 基于游标的分页。
+// 排序的列组合起来必须是唯一的，一般最后一列是主键，并且这些列不能是 NULL
+type Paginator[T any] struct {
+	s      *Selector[T]
+	size   int
+	orders []OrderBy
+}
+
+// Page 一页数据。NextCursor 和 PrevCursor 为空说明没有下一页或者上一页
+type Page[T any] struct {
+	Items      []*T
+	NextCursor string
+	PrevCursor string
+}
+
+// cursor 编码之后对外是不透明的字符串
+type cursor struct {
+	Direction string            `json:"d"`
+	Values    []json.RawMessage `json:"v"`
+}
+
+// Paginate 按照 orders 分页，每一页最多 size 条，orders 只能是列。
+// 原本的 ORDER BY、LIMIT 和 OFFSET 会被忽略
+func (s *Selector[T]) Paginate(size int, orders ...OrderBy) *Paginator[T] {
+	return &Paginator[T]{
+		s:      s,
+		size:   size,
+		orders: orders,
+	}
+}
+
+// Page 查询游标对应的那一页，游标为空的时候查询第一页
+func (p *Paginator[T]) Page(ctx context.Context, token string) (*Page[T], error) {
+	if p.size <= 0 {
+		return nil, errs.NewErrInvalidPageSize(p.size)
+	}
+	if len(p.orders) == 0 {
+		return nil, errs.ErrNoCursorKeys
+	}
+	fields := make([]*model.Field, 0, len(p.orders))
+	cols := make([]Column, 0, len(p.orders))
+	for _, ob := range p.orders {
+		col, ok := ob.expr.(Column)
+		if !ok {
+			return nil, errs.NewErrUnsupportedExpression(ob.expr)
+		}
+		fd, ok := p.s.model.FieldMap[col.Name]
+		if !ok {
+			return nil, errs.NewErrUnknownField(col.Name)
+		}
+		fields = append(fields, fd)
+		cols = append(cols, col)
+	}
+
+	cur := cursor{
+		Direction: cursorNext,
+	}
+	var vals []any
+	if token != "" {
+		var err error
+		cur, vals, err = p.decode(token, fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+	backward := cur.Direction == cursorPrev
+
+	ps := p.s.derive()
+	// 游标里面的值是从结果里面读出来的，所以结果里面一定要有排序的列
+	ps.columns = withColumns(p.s.columns, cols...)
+	ps.where = append(make([]Predicate, 0, len(p.s.where)+1), p.s.where...)
+	if vals != nil {
+		ps.where = append(ps.where, p.keysetPredicate(vals, backward))
+	}
+	ps.orderBy = make([]OrderBy, 0, len(p.orders))
+	for _, ob := range p.orders {
+		if backward {
+			ob = reverseOrder(ob)
+		}
+		ps.orderBy = append(ps.orderBy, ob)
+	}
+	// 多查一条，判断后面还有没有数据
+	ps.limit = p.size + 1
+	ps.offset = 0
+
+	res := getMulti[T](ctx, p.s.session, p.s.core, &QueryContext{
+		Type:    TypeSelect,
+		Builder: ps,
+		Model:   p.s.model,
+	})
+	if res.Err != nil && !errors.Is(res.Err, ErrNoRows) {
+		return nil, res.Err
+	}
+	var items []*T
+	if res.Result != nil {
+		items = res.Result.([]*T)
+	}
+	hasMore := len(items) > p.size
+	if hasMore {
+		items = items[:p.size]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := &Page[T]{
+		Items: items,
+	}
+	if len(items) == 0 {
+		return page, nil
+	}
+	// 往前翻的时候，后面一定还有数据；往后翻的时候，只要不是第一页，前面一定还有数据
+	if backward || hasMore {
+		next, err := p.encode(cursorNext, items[len(items)-1], fields)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+	}
+	if backward && hasMore || !backward && token != "" {
+		prev, err := p.encode(cursorPrev, items[0], fields)
+		if err != nil {
+			return nil, err
+		}
+		page.PrevCursor = prev
+	}
+	return page, nil
+}
+
+// keysetPredicate 构造多列的元组比较，例如 ORDER BY a ASC, b DESC 往后翻页：
+//
+//	a > ? OR (a = ? AND b < ?)
+func (p *Paginator[T]) keysetPredicate(vals []any, backward bool) Predicate {
+	var res Predicate
+	for i, ob := range p.orders {
+		col := ob.expr.(Column)
+		var cur Predicate
+		if (ob.order == "ASC") != backward {
+			cur = col.Gt(vals[i])
+		} else {
+			cur = col.Lt(vals[i])
+		}
+		for j := i - 1; j >= 0; j-- {
+			cur = p.orders[j].expr.(Column).Eq(vals[j]).And(cur)
+		}
+		if i == 0 {
+			res = cur
+		} else {
+			res = res.Or(cur)
+		}
+	}
+	return res
+}
+
+func (p *Paginator[T]) encode(direction string, item *T, fields []*model.Field) (string, error) {
+	val := p.s.creator(p.s.model, item)
+	cur := cursor{
+		Direction: direction,
+		Values:    make([]json.RawMessage, 0, len(fields)),
+	}
+	for _, fd := range fields {
+		v, err := val.Field(fd.GoName)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		cur.Values = append(cur.Values, data)
+	}
+	data, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decode 游标里面的值按照字段的类型解析，避免数字都变成 float64
+func (p *Paginator[T]) decode(token string, fields []*model.Field) (cursor, []any, error) {
+	var cur cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cur, nil, errs.NewErrInvalidCursor(token)
+	}
+	if err = json.Unmarshal(data, &cur); err != nil {
+		return cur, nil, errs.NewErrInvalidCursor(token)
+	}
+	if len(cur.Values) != len(fields) ||
+		cur.Direction != cursorNext && cur.Direction != cursorPrev {
+		return cur, nil, errs.NewErrInvalidCursor(token)
+	}
+	vals := make([]any, 0, len(fields))
+	for i, fd := range fields {
+		v := reflect.New(fd.Typ)
+		if err = json.Unmarshal(cur.Values[i], v.Interface()); err != nil {
+			return cur, nil, errs.NewErrInvalidCursor(token)
+		}
+		vals = append(vals, v.Elem().Interface())
+	}
+	return cur, vals, nil
+}
+
+func reverseOrder(ob OrderBy) OrderBy {
+	if ob.order == "ASC" {
+		ob.order = "DESC"
+	} else {
+		ob.order = "ASC"
+	}
+	return ob
+}
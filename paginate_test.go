@@ -0,0 +1,155 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPaginator_keysetPredicate(t *testing.T) {
+	db := memoryDB(t)
+	testCases := []struct {
+		name     string
+		orders   []OrderBy
+		backward bool
+		vals     []any
+		wantSQL  string
+	}{
+		{
+			name:    "single",
+			orders:  []OrderBy{Asc(C("Id"))},
+			vals:    []any{1},
+			wantSQL: "SELECT * FROM `test_model` WHERE `id` > ?;",
+		},
+		{
+			name:     "single backward",
+			orders:   []OrderBy{Asc(C("Id"))},
+			backward: true,
+			vals:     []any{1},
+			wantSQL:  "SELECT * FROM `test_model` WHERE `id` < ?;",
+		},
+		{
+			name:    "mixed",
+			orders:  []OrderBy{Desc(C("Age")), Asc(C("Id"))},
+			vals:    []any{18, 1},
+			wantSQL: "SELECT * FROM `test_model` WHERE (`age` < ?) OR ((`age` = ?) AND (`id` > ?));",
+		},
+		{
+			name:     "mixed backward",
+			orders:   []OrderBy{Desc(C("Age")), Asc(C("Id"))},
+			backward: true,
+			vals:     []any{18, 1},
+			wantSQL:  "SELECT * FROM `test_model` WHERE (`age` > ?) OR ((`age` = ?) AND (`id` < ?));",
+		},
+		{
+			name:    "three columns",
+			orders:  []OrderBy{Asc(C("FirstName")), Desc(C("Age")), Asc(C("Id"))},
+			vals:    []any{"Tom", 18, 1},
+			wantSQL: "SELECT * FROM `test_model` WHERE ((`first_name` > ?) OR ((`first_name` = ?) AND (`age` < ?))) OR ((`first_name` = ?) AND ((`age` = ?) AND (`id` > ?)));",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewSelector[TestModel](db)
+			p := s.Paginate(10, tc.orders...)
+			q, err := s.Where(p.keysetPredicate(tc.vals, tc.backward)).Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSQL, q.SQL)
+		})
+	}
+}
+
+func TestPaginator_Page(t *testing.T) {
+	db := memoryDB(t, WithDialect(DialectSQLite))
+	ctx := context.Background()
+	require.NoError(t, RawQuery[PageItem](db, "CREATE TABLE IF NOT EXISTS `page_item`("+
+		"`id` INTEGER PRIMARY KEY, `score` INTEGER, `name` TEXT)").Exec(ctx).Err())
+	require.NoError(t, RawQuery[PageItem](db, "DELETE FROM `page_item`").Exec(ctx).Err())
+	require.NoError(t, NewInserter[PageItem](db).Values(
+		&PageItem{Id: 1, Score: 90, Name: "a"},
+		&PageItem{Id: 2, Score: 80, Name: "b"},
+		&PageItem{Id: 3, Score: 90, Name: "c"},
+		&PageItem{Id: 4, Score: 70, Name: "d"},
+		&PageItem{Id: 5, Score: 80, Name: "e"},
+		&PageItem{Id: 6, Score: 60, Name: "f"},
+		&PageItem{Id: 7, Score: 90, Name: "g"},
+	).Exec(ctx).Err())
+
+	ids := func(page *Page[PageItem]) []int64 {
+		res := make([]int64, 0, len(page.Items))
+		for _, item := range page.Items {
+			res = append(res, item.Id)
+		}
+		return res
+	}
+
+	// 按照 score 降序，id 升序：1 3 7 | 2 5 4 | 6
+	p := NewSelector[PageItem](db).Where(C("Score").Ge(60)).
+		Paginate(3, Desc(C("Score")), Asc(C("Id")))
+
+	first, err := p.Page(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 7}, ids(first))
+	assert.Empty(t, first.PrevCursor)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := p.Page(ctx, first.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 5, 4}, ids(second))
+	require.NotEmpty(t, second.PrevCursor)
+	require.NotEmpty(t, second.NextCursor)
+
+	last, err := p.Page(ctx, second.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{6}, ids(last))
+	assert.Empty(t, last.NextCursor)
+	require.NotEmpty(t, last.PrevCursor)
+
+	back, err := p.Page(ctx, last.PrevCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 5, 4}, ids(back))
+	require.NotEmpty(t, back.PrevCursor)
+	assert.NotEmpty(t, back.NextCursor)
+
+	front, err := p.Page(ctx, back.PrevCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 7}, ids(front))
+	assert.Empty(t, front.PrevCursor)
+	assert.Equal(t, first.NextCursor, front.NextCursor)
+
+	// 没有选择排序的列的时候也要带上它们，不然游标里面都是零值
+	partial := NewSelector[PageItem](db).Select(C("Name")).Where(C("Score").Ge(60)).
+		Paginate(3, Desc(C("Score")), Asc(C("Id")))
+	first, err = partial.Page(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 7}, ids(first))
+	second, err = partial.Page(ctx, first.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 5, 4}, ids(second))
+
+	_, err = p.Page(ctx, "invalid")
+	assert.Equal(t, errs.NewErrInvalidCursor("invalid"), err)
+
+	_, err = NewSelector[PageItem](db).Paginate(3).Page(ctx, "")
+	assert.Equal(t, errs.ErrNoCursorKeys, err)
+
+	_, err = NewSelector[PageItem](db).Paginate(0, Asc(C("Id"))).Page(ctx, "")
+	assert.Equal(t, errs.NewErrInvalidPageSize(0), err)
+
+	_, err = NewSelector[PageItem](db).Paginate(3, Asc(Raw("score"))).Page(ctx, "")
+	assert.Equal(t, errs.NewErrUnsupportedExpression(Raw("score")), err)
+
+	empty, err := NewSelector[PageItem](db).Where(C("Score").Gt(100)).
+		Paginate(3, Asc(C("Id"))).Page(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Items)
+	assert.Empty(t, empty.NextCursor)
+}
+
+type PageItem struct {
+	Id    int64
+	Score int64
+	Name  string
+}
@@ -12,12 +12,30 @@ func (o op) String() string {
 }
 
 const (
-	opEQ  op = "="
-	opLT  op = "<"
-	opGT  op = ">"
-	opNOT op = "NOT"
-	opAND op = "AND"
-	opOR  op = "OR"
+	opEQ        op = "="
+	opNE        op = "!="
+	opLT        op = "<"
+	opLE        op = "<="
+	opGT        op = ">"
+	opGE        op = ">="
+	opLike      op = "LIKE"
+	opNotLike   op = "NOT LIKE"
+	opIn        op = "IN"
+	opNotIn     op = "NOT IN"
+	opBetween   op = "BETWEEN"
+	opIsNull    op = "IS NULL"
+	opIsNotNull op = "IS NOT NULL"
+	opExists    op = "EXISTS"
+	opNotExists op = "NOT EXISTS"
+	opNOT       op = "NOT"
+	opAND       op = "AND"
+	opOR        op = "OR"
+
+	opAdd   op = "+"
+	opSub   op = "-"
+	opMulti op = "*"
+	opDiv   op = "/"
+	opMod   op = "%"
 )
 
 type Predicate struct {
@@ -56,3 +74,10 @@ type Value struct {
 }
 
 func (Value) expr() {}
+
+// values 是 IN 后面的值列表
+type values struct {
+	vals []any
+}
+
+func (values) expr() {}
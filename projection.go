@@ -0,0 +1,65 @@
+package gsql
+
+import "context"
+
+// Projector 把 Selector 的结果映射到 R 上面。
+// R 不需要是表对应的模型，结果集里面的列或者别名按照 R 的元数据映射
+type Projector[R any, T any] struct {
+	s *Selector[T]
+}
+
+// SelectInto 使用 s 查询，但是把结果扫描到 R 里面，例如：
+//
+//	SelectInto[OrderStat](NewSelector[Order](db).Select(C("UserId"), Sum("Amount").As("total")))
+func SelectInto[R any, T any](s *Selector[T]) *Projector[R, T] {
+	return &Projector[R, T]{
+		s: s,
+	}
+}
+
+func (p *Projector[R, T]) Get(ctx context.Context) (*R, error) {
+	c, err := p.core()
+	if err != nil {
+		return nil, err
+	}
+	res := get[R](ctx, p.s.session, c, &QueryContext{
+		Type:    TypeSelect,
+		Builder: p.s,
+		Model:   p.s.model,
+	})
+
+	if res.Result != nil {
+		return res.Result.(*R), nil
+	}
+
+	return nil, res.Err
+}
+
+func (p *Projector[R, T]) GetMulti(ctx context.Context) ([]*R, error) {
+	c, err := p.core()
+	if err != nil {
+		return nil, err
+	}
+	res := getMulti[R](ctx, p.s.session, c, &QueryContext{
+		Type:    TypeSelect,
+		Builder: p.s,
+		Model:   p.s.model,
+	})
+
+	if res.Result != nil {
+		return res.Result.([]*R), nil
+	}
+
+	return nil, res.Err
+}
+
+// core 构造 SQL 使用的依旧是 T 的元数据，扫描结果使用 R 的元数据
+func (p *Projector[R, T]) core() (core, error) {
+	m, err := p.s.r.Get(new(R))
+	if err != nil {
+		return core{}, err
+	}
+	c := p.s.core
+	c.model = m
+	return c, nil
+}
@@ -0,0 +1,98 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSelectInto_Get(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `first_name`,COUNT\\(`id`\\) AS `cnt` FROM `test_model` GROUP BY `first_name`;").
+		WillReturnRows(sqlmock.NewRows([]string{"first_name", "cnt"}).AddRow("Tom", 3))
+	mock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"first_name", "cnt"}))
+	mock.ExpectQuery("SELECT .*").
+		WillReturnRows(sqlmock.NewRows([]string{"first_name", "age"}).AddRow("Tom", 18))
+
+	testCases := []struct {
+		name string
+		p    *Projector[NameCount, TestModel]
+
+		wantErr error
+		wantRes *NameCount
+	}{
+		{
+			name: "get row",
+			p: SelectInto[NameCount](NewSelector[TestModel](db).
+				Select(C("FirstName"), Count("Id").As("cnt")).
+				GroupBy(C("FirstName"))),
+			wantRes: &NameCount{FirstName: "Tom", Cnt: 3},
+		},
+		{
+			name: "no rows",
+			p: SelectInto[NameCount](NewSelector[TestModel](db).
+				Select(C("FirstName"), Count("Id").As("cnt")).
+				GroupBy(C("FirstName"))),
+			wantErr: errs.ErrNoRows,
+		},
+		{
+			name: "unknown column",
+			p: SelectInto[NameCount](NewSelector[TestModel](db).
+				Select(C("FirstName"), C("Age"))),
+			wantErr: errs.NewErrUnknownColumn("age"),
+		},
+		{
+			name: "build error",
+			p: SelectInto[NameCount](NewSelector[TestModel](db).
+				Select(C("Invalid"))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, er := tc.p.Get(context.Background())
+			assert.Equal(t, tc.wantErr, er)
+			if er != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
+
+func TestSelectInto_GetMulti(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT `first_name`,COUNT\\(`id`\\) AS `cnt` FROM `test_model` GROUP BY `first_name`;").
+		WillReturnRows(sqlmock.NewRows([]string{"first_name", "cnt"}).
+			AddRow("Tom", 3).AddRow("Jerry", 2))
+
+	res, err := SelectInto[NameCount](NewSelector[TestModel](db).
+		Select(C("FirstName"), Count("Id").As("cnt")).
+		GroupBy(C("FirstName"))).
+		GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*NameCount{
+		{FirstName: "Tom", Cnt: 3},
+		{FirstName: "Jerry", Cnt: 2},
+	}, res)
+
+	_, err = SelectInto[int](NewSelector[TestModel](db)).GetMulti(context.Background())
+	assert.Equal(t, errs.ErrInvalidType, err)
+}
+
+type NameCount struct {
+	FirstName string
+	Cnt       int64
+}
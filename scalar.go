@@ -0,0 +1,93 @@
+package gsql
+
+import "context"
+
+// Count 返回满足条件的行数。
+// 有 GROUP BY、DISTINCT、LIMIT 或者 OFFSET 的时候，统计的是原本查询的结果有多少行
+func (s *Selector[T]) Count(ctx context.Context) (int64, error) {
+	res, err := scalars[int64](ctx, s, s.countSelector())
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, nil
+	}
+	return res[0], nil
+}
+
+// Exists 判断是否有满足条件的行
+func (s *Selector[T]) Exists(ctx context.Context) (bool, error) {
+	ns := s.derive()
+	ns.columns = []Selectable{Raw("1")}
+	ns.distinct = false
+	ns.orderBy = nil
+	ns.limit = 1
+	res, err := scalars[int](ctx, s, ns)
+	if err != nil {
+		return false, err
+	}
+	return len(res) > 0, nil
+}
+
+// Pluck 只查询 col 一列，结果按照 V 的类型返回。
+// 沿用 s 的 WHERE、JOIN、ORDER BY 和 LIMIT，例如：
+//
+//	names, err := Pluck[string](ctx, NewSelector[User](db).Where(C("Age").Gt(18)), C("Name"))
+func Pluck[V any, T any](ctx context.Context, s *Selector[T], col Selectable) ([]V, error) {
+	ns := s.derive()
+	ns.columns = []Selectable{col}
+	return scalars[V](ctx, s, ns)
+}
+
+func scalars[V any, T any](ctx context.Context, s *Selector[T], q *Selector[T]) ([]V, error) {
+	res := getScalars[V](ctx, s.session, s.core, &QueryContext{
+		Type:    TypeSelect,
+		Builder: q,
+		Model:   s.model,
+	})
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	return res.Result.([]V), nil
+}
+
+func (s *Selector[T]) countSelector() *Selector[T] {
+	cnt := s.derive()
+	cnt.columns = []Selectable{Raw("COUNT(*)")}
+	cnt.orderBy = nil
+	cnt.lock = rowLock{}
+	// 模拟的 FULL OUTER JOIN 是两个查询 UNION ALL 起来的，只能在外面统计
+	emulated := s.emulateFullJoin()
+	if len(s.groupBy) == 0 && !s.distinct && s.limit == 0 && s.offset == 0 && !emulated {
+		return cnt
+	}
+
+	// 公共表表达式只能放在最外层
+	inner := s.derive()
+	inner.ctes = nil
+	inner.orderBy = nil
+	inner.lock = rowLock{}
+	if emulated && len(inner.columns) == 0 && !inner.distinct {
+		// JOIN 的两张表可能有同名的列，派生表里面不能有重复的列名
+		inner.columns = []Selectable{Raw("1")}
+	}
+	cnt.table = inner.AsSubquery("t")
+	cnt.where = nil
+	cnt.groupBy = nil
+	cnt.having = nil
+	cnt.distinct = false
+	cnt.limit = 0
+	cnt.offset = 0
+	cnt.hints = nil
+	return cnt
+}
+
+// derive 复制一份 Selector 用来构造 COUNT 这类查询，不会影响原本的 Selector
+func (s *Selector[T]) derive() *Selector[T] {
+	ns := *s
+	ns.builder = builder{
+		core:   s.core,
+		quoter: s.quoter,
+	}
+	return &ns
+}
@@ -0,0 +1,171 @@
+package gsql
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestSelector_Count(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		s        *Selector[TestModel]
+		mockSQL  string
+		mockRows *sqlmock.Rows
+		mockErr  error
+
+		wantRes int64
+		wantErr error
+	}{
+		{
+			name: "where",
+			s: NewSelector[TestModel](db).Where(C("Age").Gt(18)).
+				OrderBy(Asc(C("Id"))),
+			mockSQL:  "SELECT COUNT(*) FROM `test_model` WHERE `age` > ?;",
+			mockRows: sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3),
+			wantRes:  3,
+		},
+		{
+			name: "join",
+			s: func() *Selector[TestModel] {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel{}).As("t2")
+				return NewSelector[TestModel](db).
+					From(t1.Join(t2).On(t1.C("Id").Eq(t2.C("Age"))))
+			}(),
+			mockSQL:  "SELECT COUNT(*) FROM (`test_model` AS `t1` JOIN `test_model` AS `t2` ON `t1`.`id` = `t2`.`age`);",
+			mockRows: sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(5),
+			wantRes:  5,
+		},
+		{
+			// MySQL 使用 UNION ALL 模拟 FULL OUTER JOIN，两个查询各自统计会返回两行
+			name: "full outer join",
+			s: func() *Selector[TestModel] {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&TestModel{}).As("t2")
+				return NewSelector[TestModel](db).
+					From(t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("Age"))))
+			}(),
+			mockSQL: "SELECT COUNT(*) FROM (SELECT 1 FROM (`test_model` AS `t1` LEFT JOIN `test_model` AS `t2` ON `t1`.`id` = `t2`.`age`) " +
+				"UNION ALL SELECT 1 FROM (`test_model` AS `t1` RIGHT JOIN `test_model` AS `t2` ON `t1`.`id` = `t2`.`age`) " +
+				"WHERE `t1`.`id` IS NULL) AS `t`;",
+			mockRows: sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(7),
+			wantRes:  7,
+		},
+		{
+			name: "group by",
+			s: NewSelector[TestModel](db).Select(C("Age")).
+				Where(C("Id").Gt(1)).GroupBy(C("Age")),
+			mockSQL:  "SELECT COUNT(*) FROM (SELECT `age` FROM `test_model` WHERE `id` > ? GROUP BY `age`) AS `t`;",
+			mockRows: sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(2),
+			wantRes:  2,
+		},
+		{
+			name:     "limit",
+			s:        NewSelector[TestModel](db).Limit(10).Offset(5),
+			mockSQL:  "SELECT COUNT(*) FROM (SELECT * FROM `test_model` LIMIT ? OFFSET ?) AS `t`;",
+			mockRows: sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(10),
+			wantRes:  10,
+		},
+		{
+			name:    "query error",
+			s:       NewSelector[TestModel](db),
+			mockSQL: "SELECT COUNT(*) FROM `test_model`;",
+			mockErr: errors.New("query error"),
+			wantErr: errors.New("query error"),
+		},
+		{
+			name:    "invalid column",
+			s:       NewSelector[TestModel](db).Where(C("Invalid").Eq(1)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.mockSQL != "" {
+				exp := mock.ExpectQuery(regexp.QuoteMeta(tc.mockSQL))
+				if tc.mockErr != nil {
+					exp.WillReturnError(tc.mockErr)
+				} else {
+					exp.WillReturnRows(tc.mockRows)
+				}
+			}
+			before, _ := tc.s.Build()
+			res, err := tc.s.Count(context.Background())
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+			// Count 不能影响原本的查询
+			after, _ := tc.s.Build()
+			assert.Equal(t, before, after)
+		})
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_Exists(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM `test_model` WHERE `age` > ? LIMIT ?;")).
+		WithArgs(18, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1 FROM `test_model` WHERE `age` > ? LIMIT ?;")).
+		WithArgs(100, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	ok, err := NewSelector[TestModel](db).Where(C("Age").Gt(18)).
+		OrderBy(Desc(C("Id"))).Exists(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = NewSelector[TestModel](db).Where(C("Age").Gt(100)).Exists(context.Background())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPluck(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `first_name` FROM `test_model` WHERE `age` > ? ORDER BY `id` ASC LIMIT ?;")).
+		WithArgs(18, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"first_name"}).AddRow("Tom").AddRow("Jerry"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `test_model` WHERE `age` > ?;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `first_name` FROM `test_model`;")).
+		WillReturnRows(sqlmock.NewRows([]string{"first_name"}).AddRow("Tom"))
+
+	names, err := Pluck[string](context.Background(),
+		NewSelector[TestModel](db).Where(C("Age").Gt(18)).OrderBy(Asc(C("Id"))).Limit(2),
+		C("FirstName"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Tom", "Jerry"}, names)
+
+	ids, err := Pluck[int64](context.Background(),
+		NewSelector[TestModel](db).Where(C("Age").Gt(100)), C("Id"))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{}, ids)
+
+	_, err = Pluck[int64](context.Background(), NewSelector[TestModel](db), C("FirstName"))
+	assert.Error(t, err)
+
+	_, err = Pluck[int64](context.Background(), NewSelector[TestModel](db), C("Invalid"))
+	assert.Equal(t, errs.NewErrUnknownField("Invalid"), err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,6 +3,8 @@ package gsql
 import (
 	"context"
 	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/DaHuangQwQ/gsql/model"
+	"reflect"
 	"strings"
 )
 
@@ -12,9 +14,18 @@ type Selectable interface {
 
 type Selector[T any] struct {
 	builder
-	table   TableReference
-	columns []Selectable
-	where   []Predicate
+	table    TableReference
+	columns  []Selectable
+	where    []Predicate
+	groupBy  []Column
+	having   []Predicate
+	orderBy  []OrderBy
+	limit    int
+	offset   int
+	distinct bool
+	ctes     []commonTableExpr
+	lock     rowLock
+	hints    []string
 
 	session Session
 }
@@ -57,72 +68,258 @@ func (s *Selector[T]) Get(ctx context.Context) (*T, error) {
 }
 
 func (s *Selector[T]) GetMulti(ctx context.Context) ([]*T, error) {
-	q, err := s.Build()
-	if err != nil {
-		return nil, err
-	}
+	res := getMulti[T](ctx, s.session, s.core, &QueryContext{
+		Type:    TypeSelect,
+		Builder: s,
+		Model:   s.model,
+	})
 
-	row, err := s.session.queryContext(ctx, q.SQL, q.Args...)
-	if err != nil {
-		return nil, err
+	if res.Result != nil {
+		return res.Result.([]*T), nil
 	}
 
-	res := make([]*T, 0, 8)
-
-	for row.Next() {
-		tp := new(T)
+	return nil, res.Err
+}
 
-		val := s.builder.core.creator(s.model, tp)
+// Each 一行一行地处理结果，不会把所有的结果都加载到内存里面。
+// fn 拿到的 *T 会在下一行复用，需要保留的话要自己复制一份。
+// fn 返回 ErrStop 的时候提前结束，返回其它错误的时候 Each 返回这个错误
+func (s *Selector[T]) Each(ctx context.Context, fn func(*T) error) error {
+	return each[T](ctx, s.session, s.core, &QueryContext{
+		Type:    TypeSelect,
+		Builder: s,
+		Model:   s.model,
+	}, fn).Err
+}
 
-		err = val.SetColumns(row)
-		if err != nil {
+func (s *Selector[T]) Build() (*Query, error) {
+	s.reset()
+	if len(s.ctes) > 0 {
+		if err := s.buildWith(s.ctes); err != nil {
 			return nil, err
 		}
+	}
+	if err := s.buildSelect(); err != nil {
+		return nil, err
+	}
 
-		res = append(res, tp)
+	if len(s.orderBy) > 0 {
+		s.sb.WriteString(" ORDER BY ")
+		for i, ob := range s.orderBy {
+			if i > 0 {
+				s.sb.WriteByte(',')
+			}
+			if err := s.dialect.buildOrderBy(&s.builder, ob); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.dialect.buildLimit(&s.builder, s.limit, s.offset); err != nil {
+		return nil, err
 	}
 
-	if len(res) == 0 {
-		return nil, errs.ErrNoRows
+	if err := s.buildLock(); err != nil {
+		return nil, err
 	}
 
-	return res, nil
+	s.sb.WriteByte(';')
+
+	return &Query{
+		SQL:  s.sb.String(),
+		Args: s.args,
+	}, nil
 }
 
-func (s *Selector[T]) Build() (*Query, error) {
+// buildSelect 构造 SELECT 到 HAVING 的部分。
+// 数据库不支持 FULL OUTER JOIN 的时候，使用 LEFT JOIN 的结果 UNION ALL
+// RIGHT JOIN 里面左边没有匹配的行来模拟
+func (s *Selector[T]) buildSelect() error {
+	if !s.emulateFullJoin() {
+		return s.buildSelectFrom(s.table, nil)
+	}
+	if countFullJoin(s.table) > 1 {
+		return errs.ErrMultipleFullJoin
+	}
+	// UNION ALL 之后的 ORDER BY 不能使用带表名的列，锁和分组也没办法作用在整个结果上
+	switch {
+	case len(s.orderBy) > 0:
+		return errs.NewErrUnsupportedClause("ORDER BY with emulated FULL OUTER JOIN")
+	case s.lock.strength != "":
+		return errs.NewErrUnsupportedClause("FOR " + s.lock.strength + " with emulated FULL OUTER JOIN")
+	case len(s.groupBy) > 0 || len(s.having) > 0:
+		return errs.NewErrUnsupportedClause("GROUP BY with emulated FULL OUTER JOIN")
+	}
+	fj, _ := findFullJoin(s.table)
+	leftCol, ok := fullJoinLeftColumn(fj)
+	if !ok {
+		return errs.ErrFullJoinWithoutLeftColumn
+	}
+	if err := s.buildSelectFrom(replaceFullJoin(s.table, "LEFT JOIN"), nil); err != nil {
+		return err
+	}
+	s.sb.WriteString(" UNION ALL ")
+	return s.buildSelectFrom(replaceFullJoin(s.table, "RIGHT JOIN"), []Predicate{leftCol.IsNull()})
+}
+
+// emulateFullJoin 是否需要用 UNION ALL 模拟 FULL OUTER JOIN
+func (s *Selector[T]) emulateFullJoin() bool {
+	return !s.dialect.supportFullJoin() && countFullJoin(s.table) > 0
+}
+
+// buildSelectFrom extra 是额外的查询条件，和 WHERE 里面的条件用 AND 连接
+func (s *Selector[T]) buildSelectFrom(table TableReference, extra []Predicate) error {
 	s.sb.WriteString("SELECT ")
+	if len(s.hints) > 0 {
+		if err := s.dialect.buildOptimizerHints(&s.builder, s.hints); err != nil {
+			return err
+		}
+	}
+	if s.distinct {
+		s.sb.WriteString("DISTINCT ")
+	}
 
 	if err := s.buildColumns(); err != nil {
-		return nil, err
+		return err
 	}
 
 	s.sb.WriteString(" FROM ")
 
-	err := s.buildTable(s.table)
+	err := s.buildTable(table)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if len(s.where) > 0 {
+	where := s.where
+	if len(extra) > 0 {
+		where = make([]Predicate, 0, len(s.where)+len(extra))
+		where = append(where, s.where...)
+		where = append(where, extra...)
+	}
+	if len(where) > 0 {
 		s.sb.WriteString(" WHERE ")
+		if err = s.buildPredicates(where); err != nil {
+			return err
+		}
+	}
 
-		p := s.where[0]
-		for i := 1; i < len(s.where); i++ {
-			p = p.And(s.where[i])
+	if len(s.groupBy) > 0 {
+		s.sb.WriteString(" GROUP BY ")
+		for i, col := range s.groupBy {
+			if i > 0 {
+				s.sb.WriteByte(',')
+			}
+			col.alias = ""
+			if err = s.buildColumn(col); err != nil {
+				return err
+			}
 		}
+	}
 
-		er := s.buildExpression(p)
-		if er != nil {
-			return nil, er
+	// HAVING 和 ORDER BY 里面可以使用 SELECT 里面的别名
+	s.aliases = s.selectAliases()
+
+	if len(s.having) > 0 {
+		s.sb.WriteString(" HAVING ")
+		if err = s.buildPredicates(s.having); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	s.sb.WriteByte(';')
+func countFullJoin(table TableReference) int {
+	j, ok := table.(Join)
+	if !ok {
+		return 0
+	}
+	cnt := countFullJoin(j.left) + countFullJoin(j.right)
+	if j.typ == "FULL OUTER JOIN" {
+		cnt++
+	}
+	return cnt
+}
 
-	return &Query{
-		SQL:  s.sb.String(),
-		Args: s.args,
-	}, nil
+func findFullJoin(table TableReference) (Join, bool) {
+	j, ok := table.(Join)
+	if !ok {
+		return Join{}, false
+	}
+	if j.typ == "FULL OUTER JOIN" {
+		return j, true
+	}
+	if res, ok := findFullJoin(j.left); ok {
+		return res, true
+	}
+	return findFullJoin(j.right)
+}
+
+// fullJoinLeftColumn 找到 JOIN 左边的一个列，匹配上的行里面这个列一定不是 NULL，
+// 所以 RIGHT JOIN 里面这个列是 NULL 的行就是左边没有匹配的行
+func fullJoinLeftColumn(j Join) (Column, bool) {
+	if len(j.using) > 0 {
+		return Column{Name: j.using[0], Table: j.left}, true
+	}
+	for _, p := range j.on {
+		if col, ok := leftColumnOf(p, j.left); ok {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// leftColumnOf 只考虑 AND 连接的比较条件，OR 的时候列可能是 NULL
+func leftColumnOf(p Predicate, left TableReference) (Column, bool) {
+	switch p.op {
+	case opAND:
+		for _, expr := range []Expression{p.left, p.right} {
+			if sub, ok := expr.(Predicate); ok {
+				if col, ok := leftColumnOf(sub, left); ok {
+					return col, true
+				}
+			}
+		}
+	case opEQ, opLT, opLE, opGT, opGE:
+		for _, expr := range []Expression{p.left, p.right} {
+			if col, ok := expr.(Column); ok && col.Table != nil && containsTable(left, col.Table) {
+				return col, true
+			}
+		}
+	}
+	return Column{}, false
+}
+
+// containsTable 判断 ref 里面是否包含 target 这张表
+func containsTable(ref TableReference, target TableReference) bool {
+	switch t := ref.(type) {
+	case Table:
+		tt, ok := target.(Table)
+		return ok && tt.alias == t.alias &&
+			reflect.TypeOf(tt.entity) == reflect.TypeOf(t.entity)
+	case Subquery:
+		ts, ok := target.(Subquery)
+		return ok && ts.alias == t.alias
+	case CommonTable:
+		tc, ok := target.(CommonTable)
+		return ok && tc == t
+	case Join:
+		return containsTable(t.left, target) || containsTable(t.right, target)
+	default:
+		return false
+	}
+}
+
+func replaceFullJoin(table TableReference, typ string) TableReference {
+	j, ok := table.(Join)
+	if !ok {
+		return table
+	}
+	j.left = replaceFullJoin(j.left, typ)
+	j.right = replaceFullJoin(j.right, typ)
+	if j.typ == "FULL OUTER JOIN" {
+		j.typ = typ
+	}
+	return j
 }
 
 func (s *Selector[T]) buildTable(table TableReference) error {
@@ -139,6 +336,11 @@ func (s *Selector[T]) buildTable(table TableReference) error {
 			s.sb.WriteString(" AS ")
 			s.quote(t.alias)
 		}
+		if len(t.hints) > 0 {
+			if err = s.dialect.buildIndexHints(&s.builder, t.hints); err != nil {
+				return err
+			}
+		}
 	case Join:
 		s.sb.WriteByte('(')
 		err := s.buildTable(t.left)
@@ -179,6 +381,20 @@ func (s *Selector[T]) buildTable(table TableReference) error {
 		}
 
 		s.sb.WriteByte(')')
+	case Subquery:
+		if err := s.buildSubquery(t); err != nil {
+			return err
+		}
+		if t.alias != "" {
+			s.sb.WriteString(" AS ")
+			s.quote(t.alias)
+		}
+	case CommonTable:
+		s.quote(t.name)
+		if t.alias != "" {
+			s.sb.WriteString(" AS ")
+			s.quote(t.alias)
+		}
 	default:
 		return errs.NewErrUnsupportedTable(table)
 	}
@@ -187,6 +403,9 @@ func (s *Selector[T]) buildTable(table TableReference) error {
 
 func (s *Selector[T]) buildColumns() error {
 	if len(s.columns) == 0 {
+		if tables, ok := s.embedTables(); ok {
+			return s.buildEmbedColumns(tables)
+		}
 		s.sb.WriteByte('*')
 		return nil
 	}
@@ -202,29 +421,170 @@ func (s *Selector[T]) buildColumns() error {
 				return er
 			}
 		case Aggregate:
-			s.sb.WriteString(c.fn)
-			s.sb.WriteByte('(')
-			er := s.buildColumn(Column{
-				Name: c.arg,
-			})
-			s.sb.WriteByte(')')
+			er := s.buildAggregate(c)
 			if er != nil {
 				return er
 			}
-			// 聚合函数的别名
+		case RawExpr:
+			s.sb.WriteString(c.raw)
+			s.addArgs(c.args...)
+		case MathExpr:
+			if er := s.buildBinaryExpr(binaryExpr(c)); er != nil {
+				return er
+			}
 			if c.alias != "" {
 				s.sb.WriteString(" AS ")
 				s.quote(c.alias)
 			}
-		case RawExpr:
-			s.sb.WriteString(c.raw)
-			s.addArgs(c.args...)
+		case WindowFunc:
+			if er := s.buildWindowFunc(c); er != nil {
+				return er
+			}
+		case CaseExpr:
+			if er := s.buildCase(c); er != nil {
+				return er
+			}
+		case FuncExpr:
+			if er := s.buildFunc(c); er != nil {
+				return er
+			}
 		}
 	}
 
 	return nil
 }
 
+// embedTable 组合结构体里面的子结构体和它在 JOIN 里面对应的表
+type embedTable struct {
+	embed *model.Embed
+	table Table
+}
+
+// embedTables 结果是组合结构体的时候，按照子结构体的类型找到 JOIN 里面对应的表。
+// 只要有一个子结构体找不到对应的表，例如嵌入了公共的字段，就不展开
+func (s *Selector[T]) embedTables() ([]embedTable, bool) {
+	j, ok := s.table.(Join)
+	if !ok {
+		return nil, false
+	}
+	res := make([]embedTable, 0, len(s.model.Embeds))
+	for _, embed := range s.model.Embeds {
+		if strings.Contains(embed.GoName, ".") {
+			continue
+		}
+		tbl, ok := findEmbedTable(j, embed.Typ)
+		if !ok {
+			return nil, false
+		}
+		res = append(res, embedTable{embed: embed, table: tbl})
+	}
+	return res, len(res) > 0
+}
+
+// buildEmbedColumns 把每张表的列加上子结构体的前缀作为别名，
+// 这样结果集里面的列才能映射到不同的子结构体
+func (s *Selector[T]) buildEmbedColumns(tables []embedTable) error {
+	cnt := 0
+	for _, et := range tables {
+		m, err := s.r.Get(et.table.entity)
+		if err != nil {
+			return err
+		}
+		for _, fd := range m.Fields {
+			if cnt > 0 {
+				s.sb.WriteByte(',')
+			}
+			cnt++
+			// 没有别名的时候使用表名，避免不同的表有同名的列
+			if et.table.alias != "" {
+				s.quote(et.table.alias)
+			} else {
+				s.quote(m.TableName)
+			}
+			s.sb.WriteByte('.')
+			s.quote(fd.ColName)
+			if et.embed.Prefix != "" {
+				s.sb.WriteString(" AS ")
+				s.quote(et.embed.Prefix + fd.ColName)
+			}
+		}
+	}
+	return nil
+}
+
+// findEmbedTable 找到 JOIN 里面实体类型是 typ 的表
+func findEmbedTable(j Join, typ reflect.Type) (Table, bool) {
+	for _, ref := range []TableReference{j.left, j.right} {
+		switch t := ref.(type) {
+		case Table:
+			entityTyp := reflect.TypeOf(t.entity)
+			for entityTyp.Kind() == reflect.Pointer {
+				entityTyp = entityTyp.Elem()
+			}
+			if entityTyp == typ {
+				return t, true
+			}
+		case Join:
+			if res, ok := findEmbedTable(t, typ); ok {
+				return res, true
+			}
+		}
+	}
+	return Table{}, false
+}
+
+func (s *Selector[T]) selectAliases() map[string]struct{} {
+	aliases := make(map[string]struct{}, len(s.columns))
+	for _, col := range s.columns {
+		if alias := aliasOf(col); alias != "" {
+			aliases[alias] = struct{}{}
+		}
+	}
+	return aliases
+}
+
+// aliasOf 返回 SELECT 里面的列声明的别名
+func aliasOf(col Selectable) string {
+	switch c := col.(type) {
+	case Column:
+		return c.alias
+	case Aggregate:
+		return c.alias
+	case MathExpr:
+		return c.alias
+	case WindowFunc:
+		return c.alias
+	case CaseExpr:
+		return c.alias
+	case FuncExpr:
+		return c.alias
+	default:
+		return ""
+	}
+}
+
+// withColumns 指定了列的时候，把缺少的列加到后面，
+// 保证扫描出来的结果里面一定有这些列
+func withColumns(columns []Selectable, cols ...Column) []Selectable {
+	if len(columns) == 0 {
+		return nil
+	}
+	res := append(make([]Selectable, 0, len(columns)+len(cols)), columns...)
+	for _, col := range cols {
+		found := false
+		for _, c := range columns {
+			if c, ok := c.(Column); ok && c.Name == col.Name && c.alias == "" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, col)
+		}
+	}
+	return res
+}
+
 func (s *Selector[T]) From(table TableReference) *Selector[T] {
 	s.table = table
 	return s
@@ -235,7 +595,38 @@ func (s *Selector[T]) Select(cols ...Selectable) *Selector[T] {
 	return s
 }
 
+// Distinct SELECT DISTINCT
+func (s *Selector[T]) Distinct() *Selector[T] {
+	s.distinct = true
+	return s
+}
+
 func (s *Selector[T]) Where(p ...Predicate) *Selector[T] {
 	s.where = p
 	return s
 }
+
+func (s *Selector[T]) GroupBy(cols ...Column) *Selector[T] {
+	s.groupBy = cols
+	return s
+}
+
+func (s *Selector[T]) Having(ps ...Predicate) *Selector[T] {
+	s.having = ps
+	return s
+}
+
+func (s *Selector[T]) OrderBy(obs ...OrderBy) *Selector[T] {
+	s.orderBy = obs
+	return s
+}
+
+func (s *Selector[T]) Limit(limit int) *Selector[T] {
+	s.limit = limit
+	return s
+}
+
+func (s *Selector[T]) Offset(offset int) *Selector[T] {
+	s.offset = offset
+	return s
+}
@@ -14,6 +14,7 @@ import (
 
 func TestSelector_Join(t *testing.T) {
 	db := memoryDB(t)
+	pgDB := memoryDB(t, WithDialect(DialectPostgreSQL))
 	type Order struct {
 		Id        int
 		UsingCol1 string
@@ -123,6 +124,816 @@ func TestSelector_Join(t *testing.T) {
 				SQL: "SELECT * FROM (`item` AS `t4` JOIN (`order` AS `t1` JOIN `order_detail` AS `t2` ON `t1`.`id` = `t2`.`order_id`) ON `t2`.`item_id` = `t4`.`id`);",
 			},
 		},
+		{
+			name: "cross join",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&Item{}).As("t2")
+				return NewSelector[Order](db).From(t1.CrossJoin(t2))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM (`order` AS `t1` CROSS JOIN `item` AS `t2`);",
+			},
+		},
+		{
+			name: "join column",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.Join(t2).On(t1.C("Id").Eq(t2.C("OrderId")))
+				return NewSelector[Order](db).
+					Select(t3.C("Id"), t3.C("ItemId")).
+					From(t3).
+					Where(t3.C("ItemId").Eq(1))
+			}(),
+			wantQuery: &Query{
+				SQL:  "SELECT `t1`.`id`,`t2`.`item_id` FROM (`order` AS `t1` JOIN `order_detail` AS `t2` ON `t1`.`id` = `t2`.`order_id`) WHERE `t2`.`item_id` = ?;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "join column unknown",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&Item{}).As("t2")
+				t3 := t1.CrossJoin(t2)
+				return NewSelector[Order](db).Select(t3.C("Invalid")).From(t3)
+			}(),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name: "subquery join",
+			s: func() QueryBuilder {
+				sub := NewSelector[OrderDetail](db).AsSubquery("sub")
+				t1 := TableOf(&Order{}).As("t1")
+				return NewSelector[Order](db).
+					Select(sub.C("ItemId")).
+					From(sub.Join(t1).On(sub.C("OrderId").Eq(t1.C("Id"))))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT `sub`.`item_id` FROM ((SELECT * FROM `order_detail`) AS `sub` JOIN `order` AS `t1` ON `sub`.`order_id` = `t1`.`id`);",
+			},
+		},
+		{
+			name: "full outer join mysql",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("OrderId")))
+				return NewSelector[Order](db).
+					Select(t1.C("Id"), t2.C("ItemId")).
+					From(t3).
+					Where(t2.C("ItemId").Gt(1)).
+					Limit(10)
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT `t1`.`id`,`t2`.`item_id` FROM (`order` AS `t1` LEFT JOIN `order_detail` AS `t2` ON `t1`.`id` = `t2`.`order_id`) WHERE `t2`.`item_id` > ?" +
+					" UNION ALL SELECT `t1`.`id`,`t2`.`item_id` FROM (`order` AS `t1` RIGHT JOIN `order_detail` AS `t2` ON `t1`.`id` = `t2`.`order_id`) WHERE (`t2`.`item_id` > ?) AND (`t1`.`id` IS NULL)" +
+					" LIMIT ?;",
+				Args: []any{1, 1, 10},
+			},
+		},
+		{
+			name: "full outer join mysql without where",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t2.FullOuterJoin(t1).On(t1.C("UsingCol1").Eq("a"), t2.C("OrderId").Eq(t1.C("Id")))
+				return NewSelector[Order](db).From(t3)
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM (`order_detail` AS `t2` LEFT JOIN `order` AS `t1` ON (`t1`.`using_col1` = ?) AND (`t2`.`order_id` = `t1`.`id`))" +
+					" UNION ALL SELECT * FROM (`order_detail` AS `t2` RIGHT JOIN `order` AS `t1` ON (`t1`.`using_col1` = ?) AND (`t2`.`order_id` = `t1`.`id`)) WHERE `t2`.`order_id` IS NULL;",
+				Args: []any{"a", "a"},
+			},
+		},
+		{
+			name: "full outer join mysql using",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				return NewSelector[Order](db).From(t1.FullOuterJoin(t2).Using("UsingCol1"))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM (`order` AS `t1` LEFT JOIN `order_detail` AS `t2` USING (`using_col1`))" +
+					" UNION ALL SELECT * FROM (`order` AS `t1` RIGHT JOIN `order_detail` AS `t2` USING (`using_col1`)) WHERE `t1`.`using_col1` IS NULL;",
+			},
+		},
+		{
+			name: "full outer join mysql order by",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("OrderId")))
+				return NewSelector[Order](db).From(t3).OrderBy(Asc(t1.C("Id")))
+			}(),
+			wantErr: errs.NewErrUnsupportedClause("ORDER BY with emulated FULL OUTER JOIN"),
+		},
+		{
+			name: "full outer join mysql lock",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("OrderId")))
+				return NewSelector[Order](db).From(t3).ForUpdate()
+			}(),
+			wantErr: errs.NewErrUnsupportedClause("FOR UPDATE with emulated FULL OUTER JOIN"),
+		},
+		{
+			name: "full outer join mysql group by",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("OrderId")))
+				return NewSelector[Order](db).From(t3).GroupBy(t1.C("Id"))
+			}(),
+			wantErr: errs.NewErrUnsupportedClause("GROUP BY with emulated FULL OUTER JOIN"),
+		},
+		{
+			name: "full outer join mysql without left column",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.FullOuterJoin(t2).On(t2.C("OrderId").Eq(1).Or(t1.C("Id").Eq(t2.C("OrderId"))))
+				return NewSelector[Order](db).From(t3)
+			}(),
+			wantErr: errs.ErrFullJoinWithoutLeftColumn,
+		},
+		{
+			name: "full outer join mysql compound part",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("OrderId")))
+				return NewSelector[Order](db).Select(t1.C("Id")).
+					Union(NewSelector[Order](db).Select(t1.C("Id")).From(t3))
+			}(),
+			wantErr: errs.NewErrInvalidCompoundPart("emulated FULL OUTER JOIN"),
+		},
+		{
+			name: "full outer join postgres",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("OrderId")))
+				return NewSelector[Order](pgDB).From(t3)
+			}(),
+			wantQuery: &Query{
+				SQL: `SELECT * FROM ("order" AS "t1" FULL OUTER JOIN "order_detail" AS "t2" ON "t1"."id" = "t2"."order_id");`,
+			},
+		},
+		{
+			name: "multiple full outer join mysql",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&OrderDetail{}).As("t2")
+				t3 := TableOf(&Item{}).As("t3")
+				t4 := t1.FullOuterJoin(t2).On(t1.C("Id").Eq(t2.C("OrderId"))).
+					FullOuterJoin(t3).On(t2.C("ItemId").Eq(t3.C("Id")))
+				return NewSelector[Order](db).From(t4)
+			}(),
+			wantErr: errs.ErrMultipleFullJoin,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_JoinScan(t *testing.T) {
+	db := memoryDB(t, WithDialect(DialectSQLite))
+	ctx := context.Background()
+	require.NoError(t, RawQuery[JoinOrder](db, "CREATE TABLE IF NOT EXISTS `join_order`("+
+		"`id` INTEGER PRIMARY KEY, `user_id` INTEGER, `amount` INTEGER)").Exec(ctx).Err())
+	require.NoError(t, RawQuery[JoinUser](db, "CREATE TABLE IF NOT EXISTS `join_user`("+
+		"`id` INTEGER PRIMARY KEY, `name` TEXT)").Exec(ctx).Err())
+	require.NoError(t, RawQuery[JoinOrder](db, "DELETE FROM `join_order`").Exec(ctx).Err())
+	require.NoError(t, RawQuery[JoinUser](db, "DELETE FROM `join_user`").Exec(ctx).Err())
+	require.NoError(t, NewInserter[JoinUser](db).Values(
+		&JoinUser{Id: 1, Name: "Tom"},
+		&JoinUser{Id: 2, Name: "Jerry"},
+	).Exec(ctx).Err())
+	require.NoError(t, NewInserter[JoinOrder](db).Values(
+		&JoinOrder{Id: 10, UserId: 1, Amount: 100},
+		&JoinOrder{Id: 11, UserId: 2, Amount: 200},
+	).Exec(ctx).Err())
+
+	o := TableOf(&JoinOrder{}).As("o")
+	u := TableOf(&JoinUser{}).As("u")
+
+	testCases := []struct {
+		name    string
+		s       *Selector[OrderWithUser]
+		wantSQL string
+		wantRes []*OrderWithUser
+	}{
+		{
+			name: "all columns",
+			s: NewSelector[OrderWithUser](db).
+				From(o.Join(u).On(o.C("UserId").Eq(u.C("Id")))).
+				OrderBy(Asc(o.C("Id"))),
+			wantSQL: "SELECT `o`.`id`,`o`.`user_id`,`o`.`amount`,`u`.`id` AS `u_id`,`u`.`name` AS `u_name` " +
+				"FROM (`join_order` AS `o` JOIN `join_user` AS `u` ON `o`.`user_id` = `u`.`id`) ORDER BY `o`.`id` ASC;",
+			wantRes: []*OrderWithUser{
+				{JoinOrder: JoinOrder{Id: 10, UserId: 1, Amount: 100}, JoinUser: JoinUser{Id: 1, Name: "Tom"}},
+				{JoinOrder: JoinOrder{Id: 11, UserId: 2, Amount: 200}, JoinUser: JoinUser{Id: 2, Name: "Jerry"}},
+			},
+		},
+		{
+			// 没有别名的时候用表名区分同名的列
+			name: "without alias",
+			s: func() *Selector[OrderWithUser] {
+				t1 := TableOf(&JoinOrder{})
+				t2 := TableOf(&JoinUser{})
+				return NewSelector[OrderWithUser](db).
+					From(t1.Join(t2).On(Raw("`join_order`.`user_id` = `join_user`.`id`").AsPredicate())).
+					Where(t2.C("Name").Eq("Tom"))
+			}(),
+			wantSQL: "SELECT `join_order`.`id`,`join_order`.`user_id`,`join_order`.`amount`," +
+				"`join_user`.`id` AS `u_id`,`join_user`.`name` AS `u_name` " +
+				"FROM (`join_order` JOIN `join_user` ON (`join_order`.`user_id` = `join_user`.`id`)) WHERE `name` = ?;",
+			wantRes: []*OrderWithUser{
+				{JoinOrder: JoinOrder{Id: 10, UserId: 1, Amount: 100}, JoinUser: JoinUser{Id: 1, Name: "Tom"}},
+			},
+		},
+		{
+			name: "specify columns",
+			s: NewSelector[OrderWithUser](db).
+				Select(o.C("Id"), u.C("Name").As("u_name")).
+				From(o.Join(u).On(o.C("UserId").Eq(u.C("Id")))).
+				Where(u.C("Name").Eq("Jerry")),
+			wantSQL: "SELECT `o`.`id`,`u`.`name` AS `u_name` " +
+				"FROM (`join_order` AS `o` JOIN `join_user` AS `u` ON `o`.`user_id` = `u`.`id`) WHERE `u`.`name` = ?;",
+			wantRes: []*OrderWithUser{
+				{JoinOrder: JoinOrder{Id: 11}, JoinUser: JoinUser{Name: "Jerry"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSQL, q.SQL)
+			res, err := tc.s.GetMulti(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+
+	// 匿名嵌入的公共结构体不是 JOIN 里面的表，不展开子结构体的列
+	t.Run("embed shared struct", func(t *testing.T) {
+		s := NewSelector[OrderWithBase](db).
+			From(o.Join(u).On(o.C("UserId").Eq(u.C("Id"))))
+		q, err := s.Build()
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM (`join_order` AS `o` JOIN `join_user` AS `u` ON `o`.`user_id` = `u`.`id`);", q.SQL)
+
+		res, err := s.Select(o.C("Id"), o.C("UserId"), o.C("Amount")).
+			Where(u.C("Name").Eq("Jerry")).GetMulti(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []*OrderWithBase{
+			{JoinBase: JoinBase{Id: 11}, UserId: 2, Amount: 200},
+		}, res)
+	})
+}
+
+type JoinOrder struct {
+	Id     int64
+	UserId int64
+	Amount int64
+}
+
+type JoinUser struct {
+	Id   int64
+	Name string
+}
+
+type OrderWithUser struct {
+	JoinOrder
+	JoinUser `orm:"prefix=u_"`
+}
+
+type JoinBase struct {
+	Id int64
+}
+
+type OrderWithBase struct {
+	JoinBase
+	UserId int64
+	Amount int64
+}
+
+func TestSelector_Select(t *testing.T) {
+	db := memoryDB(t)
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name:    "invalid column",
+			s:       NewSelector[TestModel](db).Select(C("Invalid")),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name: "multiple columns",
+			s:    NewSelector[TestModel](db).Select(C("FirstName"), C("LastName")),
+			wantQuery: &Query{
+				SQL: "SELECT `first_name`,`last_name` FROM `test_model`;",
+			},
+		},
+		{
+			name: "columns alias",
+			s:    NewSelector[TestModel](db).Select(C("FirstName").As("my_name"), C("LastName")),
+			wantQuery: &Query{
+				SQL: "SELECT `first_name` AS `my_name`,`last_name` FROM `test_model`;",
+			},
+		},
+		{
+			name: "avg",
+			s:    NewSelector[TestModel](db).Select(Avg("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT AVG(`age`) FROM `test_model`;",
+			},
+		},
+		{
+			name: "avg alias",
+			s:    NewSelector[TestModel](db).Select(Avg("Age").As("avg_age")),
+			wantQuery: &Query{
+				SQL: "SELECT AVG(`age`) AS `avg_age` FROM `test_model`;",
+			},
+		},
+		{
+			name: "sum",
+			s:    NewSelector[TestModel](db).Select(Sum("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT SUM(`age`) FROM `test_model`;",
+			},
+		},
+		{
+			name: "count",
+			s:    NewSelector[TestModel](db).Select(Count("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT COUNT(`age`) FROM `test_model`;",
+			},
+		},
+		{
+			name: "max",
+			s:    NewSelector[TestModel](db).Select(Max("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT MAX(`age`) FROM `test_model`;",
+			},
+		},
+		{
+			name: "min",
+			s:    NewSelector[TestModel](db).Select(Min("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT MIN(`age`) FROM `test_model`;",
+			},
+		},
+		{
+			name:    "aggregate invalid columns",
+			s:       NewSelector[TestModel](db).Select(Min("Invalid")),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name: "multiple aggregate",
+			s:    NewSelector[TestModel](db).Select(Min("Age"), Max("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT MIN(`age`),MAX(`age`) FROM `test_model`;",
+			},
+		},
+		{
+			name: "distinct",
+			s:    NewSelector[TestModel](db).Distinct().Select(C("FirstName"), C("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT DISTINCT `first_name`,`age` FROM `test_model`;",
+			},
+		},
+		{
+			name: "distinct all columns",
+			s:    NewSelector[TestModel](db).Distinct(),
+			wantQuery: &Query{
+				SQL: "SELECT DISTINCT * FROM `test_model`;",
+			},
+		},
+		{
+			name: "count distinct",
+			s: NewSelector[TestModel](db).Select(C("Age"), CountDistinct("FirstName").As("cnt")).
+				GroupBy(C("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT `age`,COUNT(DISTINCT `first_name`) AS `cnt` FROM `test_model` GROUP BY `age`;",
+			},
+		},
+		{
+			name: "sum avg distinct",
+			s:    NewSelector[TestModel](db).Select(SumDistinct("Age"), AvgDistinct("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT SUM(DISTINCT `age`),AVG(DISTINCT `age`) FROM `test_model`;",
+			},
+		},
+		{
+			name: "count distinct in having",
+			s:    NewSelector[TestModel](db).Select(C("Age")).GroupBy(C("Age")).Having(CountDistinct("FirstName").Gt(1)),
+			wantQuery: &Query{
+				SQL:  "SELECT `age` FROM `test_model` GROUP BY `age` HAVING COUNT(DISTINCT `first_name`) > ?;",
+				Args: []any{1},
+			},
+		},
+		{
+			name:    "count distinct invalid column",
+			s:       NewSelector[TestModel](db).Select(CountDistinct("Invalid")),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name: "raw expression",
+			s:    NewSelector[TestModel](db).Select(Raw("COUNT(DISTINCT `first_name`)")),
+			wantQuery: &Query{
+				SQL: "SELECT COUNT(DISTINCT `first_name`) FROM `test_model`;",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_Build(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+	testCases := []struct {
+		name string
+
+		selector QueryBuilder
+
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name:     "select",
+			selector: NewSelector[TestModel](db),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model`;",
+				Args: nil,
+			},
+			wantErr: nil,
+		},
+
+		{
+			name:     "where",
+			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18)),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE `age` = ?;",
+				Args: []any{
+					18,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "empty where",
+			selector: NewSelector[TestModel](db).Where(),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model`;",
+				Args: nil,
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "not",
+			selector: NewSelector[TestModel](db).Where(Not(C("Age").Eq(18))),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE  NOT (`age` = ?);",
+				Args: []any{
+					18,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "and",
+			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18).And(C("FirstName").Eq("dahuang"))),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE (`age` = ?) AND (`first_name` = ?);",
+				Args: []any{
+					18,
+					"dahuang",
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "or",
+			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18).Or(C("FirstName").Eq("dahuang"))),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE (`age` = ?) OR (`first_name` = ?);",
+				Args: []any{
+					18,
+					"dahuang",
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "unknown field",
+			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18).Or(C("XX").Eq("dahuang"))),
+			wantErr:  errs.NewErrUnknownField("XX"),
+		},
+		{
+			name:     "raw expression as predicate",
+			selector: NewSelector[TestModel](db).Where(Raw("`id`<?", 18).AsPredicate()),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE (`id`<?);",
+				Args: []any{18},
+			},
+		},
+		{
+			name:     "raw expression used in predicate",
+			selector: NewSelector[TestModel](db).Where(C("Id").Eq(Raw("`age`+?", 1))),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` = (`age`+?);",
+				Args: []any{1},
+			},
+		},
+		{
+			name:     "columns alias in where",
+			selector: NewSelector[TestModel](db).Where(C("Id").As("my_id").Eq(18)),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` = ?;",
+				Args: []any{18},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			q, err := testCase.selector.Build()
+			assert.Equal(t, testCase.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, testCase.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_GroupBy(t *testing.T) {
+	db := memoryDB(t)
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "group by",
+			s:    NewSelector[TestModel](db).Select(C("Age"), Count("Id")).GroupBy(C("Age")),
+			wantQuery: &Query{
+				SQL: "SELECT `age`,COUNT(`id`) FROM `test_model` GROUP BY `age`;",
+			},
+		},
+		{
+			name: "group by multiple columns",
+			s:    NewSelector[TestModel](db).GroupBy(C("Age"), C("FirstName").As("name")),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` GROUP BY `age`,`first_name`;",
+			},
+		},
+		{
+			name:    "group by invalid column",
+			s:       NewSelector[TestModel](db).GroupBy(C("Invalid")),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name: "having aggregate",
+			s: NewSelector[TestModel](db).Select(C("Age")).
+				Where(C("Id").Eq(10)).
+				GroupBy(C("Age")).
+				Having(Count("Id").Gt(2), Avg("Id").Lt(100)),
+			wantQuery: &Query{
+				SQL: "SELECT `age` FROM `test_model` WHERE `id` = ? GROUP BY `age` " +
+					"HAVING (COUNT(`id`) > ?) AND (AVG(`id`) < ?);",
+				Args: []any{10, 2, 100},
+			},
+		},
+		{
+			name: "having alias",
+			s: NewSelector[TestModel](db).Select(C("Age"), Count("Id").As("cnt")).
+				GroupBy(C("Age")).
+				Having(C("cnt").Eq(2)),
+			wantQuery: &Query{
+				SQL:  "SELECT `age`,COUNT(`id`) AS `cnt` FROM `test_model` GROUP BY `age` HAVING `cnt` = ?;",
+				Args: []any{2},
+			},
+		},
+		{
+			name: "having aggregate ignores alias",
+			s: NewSelector[TestModel](db).Select(C("Age"), Count("Id").As("cnt")).
+				GroupBy(C("Age")).
+				Having(Count("Id").As("cnt").Eq(2)),
+			wantQuery: &Query{
+				SQL:  "SELECT `age`,COUNT(`id`) AS `cnt` FROM `test_model` GROUP BY `age` HAVING COUNT(`id`) = ?;",
+				Args: []any{2},
+			},
+		},
+		{
+			name: "having unknown alias",
+			s: NewSelector[TestModel](db).Select(C("Age"), Count("Id").As("cnt")).
+				GroupBy(C("Age")).
+				Having(C("total").Eq(2)),
+			wantErr: errs.NewErrUnknownField("total"),
+		},
+		{
+			name: "having invalid aggregate",
+			s: NewSelector[TestModel](db).GroupBy(C("Age")).
+				Having(Max("Invalid").Gt(2)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_OrderBy(t *testing.T) {
+	db := memoryDB(t)
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "asc",
+			s:    NewSelector[TestModel](db).OrderBy(Asc(C("Age"))),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` ORDER BY `age` ASC;",
+			},
+		},
+		{
+			name: "asc desc",
+			s:    NewSelector[TestModel](db).Where(C("Id").Eq(1)).OrderBy(Asc(C("Age")), Desc(C("Id"))),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `id` = ? ORDER BY `age` ASC,`id` DESC;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "aggregate",
+			s: NewSelector[TestModel](db).Select(C("Age")).GroupBy(C("Age")).
+				OrderBy(Desc(Count("Id"))),
+			wantQuery: &Query{
+				SQL: "SELECT `age` FROM `test_model` GROUP BY `age` ORDER BY COUNT(`id`) DESC;",
+			},
+		},
+		{
+			name: "alias",
+			s: NewSelector[TestModel](db).Select(C("Age"), Count("Id").As("cnt")).GroupBy(C("Age")).
+				OrderBy(Desc(C("cnt"))),
+			wantQuery: &Query{
+				SQL: "SELECT `age`,COUNT(`id`) AS `cnt` FROM `test_model` GROUP BY `age` ORDER BY `cnt` DESC;",
+			},
+		},
+		{
+			name: "raw expression",
+			s:    NewSelector[TestModel](db).OrderBy(Asc(Raw("FIELD(`id`,?,?)", 3, 1))),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` ORDER BY (FIELD(`id`,?,?)) ASC;",
+				Args: []any{3, 1},
+			},
+		},
+		{
+			name: "mysql nulls first",
+			s:    NewSelector[TestModel](db).OrderBy(Asc(C("LastName")).NullsFirst()),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` ORDER BY `last_name` IS NULL DESC,`last_name` ASC;",
+			},
+		},
+		{
+			name: "mysql nulls last",
+			s:    NewSelector[TestModel](db).OrderBy(Desc(C("LastName")).NullsLast()),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` ORDER BY `last_name` IS NULL ASC,`last_name` DESC;",
+			},
+		},
+		{
+			name: "sqlite nulls last",
+			s: NewSelector[TestModel](memoryDB(t, WithDialect(DialectSQLite))).
+				OrderBy(Desc(C("LastName")).NullsLast()),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` ORDER BY `last_name` DESC NULLS LAST;",
+			},
+		},
+		{
+			name:    "invalid column",
+			s:       NewSelector[TestModel](db).OrderBy(Asc(C("Invalid"))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_Limit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "limit",
+			s:    NewSelector[TestModel](memoryDB(t)).Limit(10),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` LIMIT ?;",
+				Args: []any{10},
+			},
+		},
+		{
+			name: "limit offset",
+			s: NewSelector[TestModel](memoryDB(t)).Where(C("Age").Eq(18)).
+				OrderBy(Asc(C("Id"))).Limit(10).Offset(20),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `age` = ? ORDER BY `id` ASC LIMIT ? OFFSET ?;",
+				Args: []any{18, 10, 20},
+			},
+		},
+		{
+			name: "mysql offset only",
+			s:    NewSelector[TestModel](memoryDB(t)).Offset(20),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` LIMIT 18446744073709551615 OFFSET ?;",
+				Args: []any{20},
+			},
+		},
+		{
+			name: "sqlite limit offset",
+			s:    NewSelector[TestModel](memoryDB(t, WithDialect(DialectSQLite))).Limit(10).Offset(20),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` LIMIT ? OFFSET ?;",
+				Args: []any{10, 20},
+			},
+		},
+		{
+			name: "sqlite offset only",
+			s:    NewSelector[TestModel](memoryDB(t, WithDialect(DialectSQLite))).Offset(20),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` LIMIT -1 OFFSET ?;",
+				Args: []any{20},
+			},
+		},
+		{
+			name: "postgresql offset only",
+			s:    NewSelector[TestModel](memoryDB(t, WithDialect(DialectPostgreSQL))).Offset(20),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM \"test_model\" OFFSET ?;",
+				Args: []any{20},
+			},
+		},
+		{
+			name: "postgresql limit offset",
+			s:    NewSelector[TestModel](memoryDB(t, WithDialect(DialectPostgreSQL))).Limit(10).Offset(20),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM \"test_model\" LIMIT ? OFFSET ?;",
+				Args: []any{10, 20},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -137,8 +948,12 @@ func TestSelector_Join(t *testing.T) {
 	}
 }
 
-func TestSelector_Select(t *testing.T) {
+func TestSelector_Where(t *testing.T) {
 	db := memoryDB(t)
+	type Order struct {
+		Id     int
+		UserId int
+	}
 	testCases := []struct {
 		name      string
 		s         QueryBuilder
@@ -146,85 +961,119 @@ func TestSelector_Select(t *testing.T) {
 		wantErr   error
 	}{
 		{
-			name:    "invalid column",
-			s:       NewSelector[TestModel](db).Select(C("Invalid")),
-			wantErr: errs.NewErrUnknownField("Invalid"),
+			name: "comparison",
+			s: NewSelector[TestModel](db).Where(C("Id").Ne(1), C("Age").Lt(30),
+				C("Age").Le(29), C("Age").Gt(18), C("Age").Ge(19)),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE ((((`id` != ?) AND (`age` < ?)) " +
+					"AND (`age` <= ?)) AND (`age` > ?)) AND (`age` >= ?);",
+				Args: []any{1, 30, 29, 18, 19},
+			},
 		},
 		{
-			name: "multiple columns",
-			s:    NewSelector[TestModel](db).Select(C("FirstName"), C("LastName")),
+			name: "like",
+			s:    NewSelector[TestModel](db).Where(C("FirstName").Like("da%"), C("LastName").NotLike("%huang")),
 			wantQuery: &Query{
-				SQL: "SELECT `first_name`,`last_name` FROM `test_model`;",
+				SQL:  "SELECT * FROM `test_model` WHERE (`first_name` LIKE ?) AND (`last_name` NOT LIKE ?);",
+				Args: []any{"da%", "%huang"},
 			},
 		},
 		{
-			name: "columns alias",
-			s:    NewSelector[TestModel](db).Select(C("FirstName").As("my_name"), C("LastName")),
+			name: "in",
+			s:    NewSelector[TestModel](db).Where(C("Id").In(1, 2, 3)),
 			wantQuery: &Query{
-				SQL: "SELECT `first_name` AS `my_name`,`last_name` FROM `test_model`;",
+				SQL:  "SELECT * FROM `test_model` WHERE `id` IN (?,?,?);",
+				Args: []any{1, 2, 3},
 			},
 		},
 		{
-			name: "avg",
-			s:    NewSelector[TestModel](db).Select(Avg("Age")),
+			name: "in slice",
+			s:    NewSelector[TestModel](db).Where(C("Id").In([]int64{1, 2})),
 			wantQuery: &Query{
-				SQL: "SELECT AVG(`age`) FROM `test_model`;",
+				SQL:  "SELECT * FROM `test_model` WHERE `id` IN (?,?);",
+				Args: []any{int64(1), int64(2)},
 			},
 		},
 		{
-			name: "avg alias",
-			s:    NewSelector[TestModel](db).Select(Avg("Age").As("avg_age")),
+			name: "in bytes",
+			s:    NewSelector[TestModel](db).Where(C("FirstName").In([]byte("da"))),
 			wantQuery: &Query{
-				SQL: "SELECT AVG(`age`) AS `avg_age` FROM `test_model`;",
+				SQL:  "SELECT * FROM `test_model` WHERE `first_name` IN (?);",
+				Args: []any{[]byte("da")},
 			},
 		},
 		{
-			name: "sum",
-			s:    NewSelector[TestModel](db).Select(Sum("Age")),
+			name: "in empty",
+			s:    NewSelector[TestModel](db).Where(C("Id").In()),
 			wantQuery: &Query{
-				SQL: "SELECT SUM(`age`) FROM `test_model`;",
+				SQL: "SELECT * FROM `test_model` WHERE (1=0);",
 			},
 		},
 		{
-			name: "count",
-			s:    NewSelector[TestModel](db).Select(Count("Age")),
+			name: "in empty slice",
+			s:    NewSelector[TestModel](db).Where(C("Id").In([]int{}), C("Age").Eq(18)),
 			wantQuery: &Query{
-				SQL: "SELECT COUNT(`age`) FROM `test_model`;",
+				SQL:  "SELECT * FROM `test_model` WHERE ((1=0)) AND (`age` = ?);",
+				Args: []any{18},
 			},
 		},
 		{
-			name: "max",
-			s:    NewSelector[TestModel](db).Select(Max("Age")),
+			name: "not in",
+			s:    NewSelector[TestModel](db).Where(C("Id").NotIn([]string{"a", "b"})),
 			wantQuery: &Query{
-				SQL: "SELECT MAX(`age`) FROM `test_model`;",
+				SQL:  "SELECT * FROM `test_model` WHERE `id` NOT IN (?,?);",
+				Args: []any{"a", "b"},
 			},
 		},
 		{
-			name: "min",
-			s:    NewSelector[TestModel](db).Select(Min("Age")),
+			name: "not in empty",
+			s:    NewSelector[TestModel](db).Where(C("Id").NotIn()),
 			wantQuery: &Query{
-				SQL: "SELECT MIN(`age`) FROM `test_model`;",
+				SQL: "SELECT * FROM `test_model` WHERE (1=1);",
 			},
 		},
 		{
-			name:    "aggregate invalid columns",
-			s:       NewSelector[TestModel](db).Select(Min("Invalid")),
-			wantErr: errs.NewErrUnknownField("Invalid"),
+			name: "between",
+			s:    NewSelector[TestModel](db).Where(C("Age").Between(18, 30)),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE `age` BETWEEN ? AND ?;",
+				Args: []any{18, 30},
+			},
 		},
 		{
-			name: "multiple aggregate",
-			s:    NewSelector[TestModel](db).Select(Min("Age"), Max("Age")),
+			name: "is null",
+			s:    NewSelector[TestModel](db).Where(C("LastName").IsNull(), C("FirstName").IsNotNull()),
 			wantQuery: &Query{
-				SQL: "SELECT MIN(`age`),MAX(`age`) FROM `test_model`;",
+				SQL: "SELECT * FROM `test_model` WHERE (`last_name` IS NULL) AND (`first_name` IS NOT NULL);",
 			},
 		},
 		{
-			name: "raw expression",
-			s:    NewSelector[TestModel](db).Select(Raw("COUNT(DISTINCT `first_name`)")),
+			name: "not is null",
+			s:    NewSelector[TestModel](db).Where(Not(C("LastName").IsNull())),
 			wantQuery: &Query{
-				SQL: "SELECT COUNT(DISTINCT `first_name`) FROM `test_model`;",
+				SQL: "SELECT * FROM `test_model` WHERE  NOT (`last_name` IS NULL);",
+			},
+		},
+		{
+			name: "table columns",
+			s: func() QueryBuilder {
+				t1 := TableOf(&Order{}).As("t1")
+				t2 := TableOf(&TestModel{}).As("t2")
+				return NewSelector[Order](db).
+					From(t1.Join(t2).On(t1.C("UserId").Eq(t2.C("Id")))).
+					Where(t2.C("Age").Between(18, 30), t1.C("Id").In(1, 2), t2.C("LastName").IsNotNull())
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM (`order` AS `t1` JOIN `test_model` AS `t2` ON `t1`.`user_id` = `t2`.`id`) " +
+					"WHERE ((`t2`.`age` BETWEEN ? AND ?) AND (`t1`.`id` IN (?,?))) AND (`t2`.`last_name` IS NOT NULL);",
+				Args: []any{18, 30, 1, 2},
 			},
 		},
+		{
+			name:    "invalid column",
+			s:       NewSelector[TestModel](db).Where(C("Invalid").In(1)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -239,122 +1088,157 @@ func TestSelector_Select(t *testing.T) {
 	}
 }
 
-func TestSelector_Build(t *testing.T) {
-	mockDB, _, err := sqlmock.New()
-	db, err := OpenDB(mockDB)
-	require.NoError(t, err)
+func TestSelector_MathExpr(t *testing.T) {
+	db := memoryDB(t)
 	testCases := []struct {
-		name string
-
-		selector QueryBuilder
-
+		name      string
+		s         QueryBuilder
 		wantQuery *Query
 		wantErr   error
 	}{
 		{
-			name:     "select",
-			selector: NewSelector[TestModel](db),
+			name: "where",
+			s:    NewSelector[TestModel](db).Where(C("Age").Add(1).Gt(18)),
 			wantQuery: &Query{
-				SQL:  "SELECT * FROM `test_model`;",
-				Args: nil,
+				SQL:  "SELECT * FROM `test_model` WHERE `age` + ? > ?;",
+				Args: []any{1, 18},
 			},
-			wantErr: nil,
 		},
-
 		{
-			name:     "where",
-			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18)),
+			name: "nested",
+			s:    NewSelector[TestModel](db).Where(C("Age").Add(1).Multi(2).Le(C("Id").Sub(C("Age").Div(3)))),
 			wantQuery: &Query{
-				SQL: "SELECT * FROM `test_model` WHERE `age` = ?;",
-				Args: []any{
-					18,
-				},
+				SQL:  "SELECT * FROM `test_model` WHERE (`age` + ?) * ? <= `id` - (`age` / ?);",
+				Args: []any{1, 2, 3},
 			},
-			wantErr: nil,
 		},
 		{
-			name:     "empty where",
-			selector: NewSelector[TestModel](db).Where(),
+			name: "mod",
+			s:    NewSelector[TestModel](db).Where(C("Id").Mod(2).Eq(0), C("Age").Sub(1).Ne(17)),
 			wantQuery: &Query{
-				SQL:  "SELECT * FROM `test_model`;",
-				Args: nil,
+				SQL:  "SELECT * FROM `test_model` WHERE (`id` % ? = ?) AND (`age` - ? != ?);",
+				Args: []any{2, 0, 1, 17},
 			},
-			wantErr: nil,
 		},
 		{
-			name:     "not",
-			selector: NewSelector[TestModel](db).Where(Not(C("Age").Eq(18))),
+			name: "select alias",
+			s: NewSelector[TestModel](db).Select(C("Id"), C("Age").Add(1).As("next_age")).
+				OrderBy(Desc(C("next_age"))),
 			wantQuery: &Query{
-				SQL: "SELECT * FROM `test_model` WHERE  NOT (`age` = ?);",
-				Args: []any{
-					18,
-				},
+				SQL:  "SELECT `id`,`age` + ? AS `next_age` FROM `test_model` ORDER BY `next_age` DESC;",
+				Args: []any{1},
 			},
-			wantErr: nil,
 		},
 		{
-			name:     "and",
-			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18).And(C("FirstName").Eq("dahuang"))),
+			name: "select without alias",
+			s:    NewSelector[TestModel](db).Select(C("Age").Multi(C("Id"))),
 			wantQuery: &Query{
-				SQL: "SELECT * FROM `test_model` WHERE (`age` = ?) AND (`first_name` = ?);",
-				Args: []any{
-					18,
-					"dahuang",
-				},
+				SQL: "SELECT `age` * `id` FROM `test_model`;",
 			},
-			wantErr: nil,
 		},
 		{
-			name:     "or",
-			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18).Or(C("FirstName").Eq("dahuang"))),
+			name:    "invalid column",
+			s:       NewSelector[TestModel](db).Where(C("Age").Add(C("Invalid")).Gt(1)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_AggregateExpression(t *testing.T) {
+	db := memoryDB(t)
+	type OrderItem struct {
+		Id      int64
+		OrderId int64
+		Price   int64
+		Qty     int64
+	}
+	type Order struct {
+		Id     int64
+		UserId int64
+	}
+
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "math expression",
+			s: NewSelector[OrderItem](db).Select(C("OrderId"),
+				Sum(C("Price").Multi(C("Qty"))).As("total")).GroupBy(C("OrderId")),
 			wantQuery: &Query{
-				SQL: "SELECT * FROM `test_model` WHERE (`age` = ?) OR (`first_name` = ?);",
-				Args: []any{
-					18,
-					"dahuang",
-				},
+				SQL: "SELECT `order_id`,SUM(`price` * `qty`) AS `total` FROM `order_item` GROUP BY `order_id`;",
 			},
-			wantErr: nil,
 		},
 		{
-			name:     "unknown field",
-			selector: NewSelector[TestModel](db).Where(C("Age").Eq(18).Or(C("XX").Eq("dahuang"))),
-			wantErr:  errs.NewErrUnknownField("XX"),
+			name: "function",
+			s:    NewSelector[TestModel](db).Select(Max(Length(C("FirstName")))),
+			wantQuery: &Query{
+				SQL: "SELECT MAX(CHAR_LENGTH(`first_name`)) FROM `test_model`;",
+			},
 		},
 		{
-			name:     "raw expression as predicate",
-			selector: NewSelector[TestModel](db).Where(Raw("`id`<?", 18).AsPredicate()),
+			name: "table columns",
+			s: func() QueryBuilder {
+				o := TableOf(&Order{}).As("o")
+				i := TableOf(&OrderItem{}).As("i")
+				return NewSelector[Order](db).
+					Select(o.C("UserId"), CountDistinct(o.C("Id")), Sum(i.C("Price").Multi(i.C("Qty")))).
+					From(o.Join(i).On(o.C("Id").Eq(i.C("OrderId")))).
+					GroupBy(o.C("UserId"))
+			}(),
 			wantQuery: &Query{
-				SQL:  "SELECT * FROM `test_model` WHERE (`id`<?);",
-				Args: []any{18},
+				SQL: "SELECT `o`.`user_id`,COUNT(DISTINCT `o`.`id`),SUM(`i`.`price` * `i`.`qty`) FROM " +
+					"(`order` AS `o` JOIN `order_item` AS `i` ON `o`.`id` = `i`.`order_id`) GROUP BY `o`.`user_id`;",
 			},
 		},
 		{
-			name:     "raw expression used in predicate",
-			selector: NewSelector[TestModel](db).Where(C("Id").Eq(Raw("`age`+?", 1))),
+			name: "having and order by",
+			s: NewSelector[OrderItem](db).Select(C("OrderId")).GroupBy(C("OrderId")).
+				Having(Sum(C("Price").Multi(C("Qty"))).Ge(100), Count("Id").Ne(1), Avg("Price").Le(50)).
+				OrderBy(Desc(Sum(C("Price").Multi(C("Qty"))))),
 			wantQuery: &Query{
-				SQL:  "SELECT * FROM `test_model` WHERE `id` = (`age`+?);",
-				Args: []any{1},
+				SQL: "SELECT `order_id` FROM `order_item` GROUP BY `order_id` " +
+					"HAVING ((SUM(`price` * `qty`) >= ?) AND (COUNT(`id`) != ?)) AND (AVG(`price`) <= ?) " +
+					"ORDER BY SUM(`price` * `qty`) DESC;",
+				Args: []any{100, 1, 50},
 			},
 		},
 		{
-			name:     "columns alias in where",
-			selector: NewSelector[TestModel](db).Where(C("Id").As("my_id").Eq(18)),
+			name: "value argument",
+			s:    NewSelector[OrderItem](db).Select(Sum(C("Price").Add(1)).As("total")),
 			wantQuery: &Query{
-				SQL:  "SELECT * FROM `test_model` WHERE `id` = ?;",
-				Args: []any{18},
+				SQL:  "SELECT SUM(`price` + ?) AS `total` FROM `order_item`;",
+				Args: []any{1},
 			},
 		},
+		{
+			name:    "invalid expression column",
+			s:       NewSelector[OrderItem](db).Select(Sum(C("Price").Multi(C("Invalid")))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
 	}
 
-	for _, testCase := range testCases {
-		t.Run(testCase.name, func(t *testing.T) {
-			q, err := testCase.selector.Build()
-			assert.Equal(t, testCase.wantErr, err)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
 			if err != nil {
 				return
 			}
-			assert.Equal(t, testCase.wantQuery, q)
+			assert.Equal(t, tc.wantQuery, q)
 		})
 	}
 }
@@ -487,6 +1371,147 @@ func TestSelector_GetMulti(t *testing.T) {
 	}
 }
 
+func TestSelector_GetMulti_Middleware(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+	var queries []string
+	db.Use(func(next Handler) Handler {
+		return func(ctx context.Context, qc *QueryContext) *QueryResult {
+			q, err := qc.BuildQuery()
+			if err != nil {
+				return &QueryResult{Err: err}
+			}
+			queries = append(queries, string(qc.Type)+": "+q.SQL)
+			if q.Args != nil && q.Args[0] == 0 {
+				return &QueryResult{Err: errors.New("blocked")}
+			}
+			return next(ctx, qc)
+		}
+	})
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow("1")
+	mock.ExpectQuery("SELECT .*").WillReturnRows(rows).RowsWillBeClosed()
+
+	res, err := NewSelector[TestModel](db).Where(C("Id").Gt(1)).GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*TestModel{{Id: 1}}, res)
+
+	// 中间件可以拦截 GetMulti
+	_, err = NewSelector[TestModel](db).Where(C("Id").Gt(0)).GetMulti(context.Background())
+	assert.Equal(t, errors.New("blocked"), err)
+
+	assert.Equal(t, []string{
+		"select: SELECT * FROM `test_model` WHERE `id` > ?;",
+		"select: SELECT * FROM `test_model` WHERE `id` > ?;",
+	}, queries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSelector_Each(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "first_name"}).
+			AddRow("1", "da").
+			AddRow("2", "xiao").
+			AddRow("3", "lao")
+	}
+
+	testCases := []struct {
+		name string
+		mock func()
+		fn   func(res *[]TestModel) func(*TestModel) error
+
+		wantRes []TestModel
+		wantErr error
+	}{
+		{
+			name: "all rows",
+			mock: func() {
+				mock.ExpectQuery("SELECT .*").WillReturnRows(newRows()).RowsWillBeClosed()
+			},
+			fn: func(res *[]TestModel) func(*TestModel) error {
+				return func(tm *TestModel) error {
+					*res = append(*res, *tm)
+					return nil
+				}
+			},
+			wantRes: []TestModel{{Id: 1, FirstName: "da"}, {Id: 2, FirstName: "xiao"}, {Id: 3, FirstName: "lao"}},
+		},
+		{
+			name: "stop",
+			mock: func() {
+				mock.ExpectQuery("SELECT .*").WillReturnRows(newRows()).RowsWillBeClosed()
+			},
+			fn: func(res *[]TestModel) func(*TestModel) error {
+				return func(tm *TestModel) error {
+					*res = append(*res, *tm)
+					if tm.Id == 2 {
+						return ErrStop
+					}
+					return nil
+				}
+			},
+			wantRes: []TestModel{{Id: 1, FirstName: "da"}, {Id: 2, FirstName: "xiao"}},
+		},
+		{
+			name: "callback error",
+			mock: func() {
+				mock.ExpectQuery("SELECT .*").WillReturnRows(newRows()).RowsWillBeClosed()
+			},
+			fn: func(res *[]TestModel) func(*TestModel) error {
+				return func(tm *TestModel) error {
+					*res = append(*res, *tm)
+					return errors.New("callback error")
+				}
+			},
+			wantRes: []TestModel{{Id: 1, FirstName: "da"}},
+			wantErr: errors.New("callback error"),
+		},
+		{
+			name: "unknown column",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"invalid"}).AddRow("1")
+				mock.ExpectQuery("SELECT .*").WillReturnRows(rows).RowsWillBeClosed()
+			},
+			fn: func(res *[]TestModel) func(*TestModel) error {
+				return func(tm *TestModel) error {
+					*res = append(*res, *tm)
+					return nil
+				}
+			},
+			wantErr: errs.NewErrUnknownColumn("invalid"),
+		},
+		{
+			name: "query error",
+			mock: func() {
+				mock.ExpectQuery("SELECT .*").WillReturnError(errors.New("query error"))
+			},
+			fn: func(res *[]TestModel) func(*TestModel) error {
+				return func(tm *TestModel) error {
+					return nil
+				}
+			},
+			wantErr: errors.New("query error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mock()
+			var res []TestModel
+			err := NewSelector[TestModel](db).Each(context.Background(), tc.fn(&res))
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func memoryDB(t *testing.T, opts ...DBOption) *DB {
 	db, err := Open("sqlite3",
 		"file:test.session?cache=shared&mode=memory",
@@ -0,0 +1,94 @@
+package gsql
+
+import "github.com/DaHuangQwQ/gsql/model"
+
+// Subquery 子查询，可以作为派生表，也可以用在 IN 和 EXISTS 里面
+type Subquery struct {
+	// 使用 QueryBuilder，屏蔽 Selector 的泛型参数
+	s       QueryBuilder
+	columns []Selectable
+	alias   string
+	model   *model.Model
+}
+
+// AsSubquery 将 Selector 转为子查询，alias 是作为派生表时候的别名
+func (s *Selector[T]) AsSubquery(alias string) Subquery {
+	return Subquery{
+		s:       s,
+		columns: s.columns,
+		alias:   alias,
+		model:   s.model,
+	}
+}
+
+func (s *Selector[T]) resultSubquery(alias string) (Subquery, bool) {
+	return s.AsSubquery(alias), true
+}
+
+// C 引用子查询里面的列，可以是字段名，也可以是子查询里面声明的别名
+func (s Subquery) C(name string) Column {
+	return Column{
+		Name:  name,
+		Table: s,
+	}
+}
+
+func (s Subquery) Join(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  s,
+		right: right,
+		typ:   "JOIN",
+	}
+}
+
+func (s Subquery) LeftJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  s,
+		right: right,
+		typ:   "LEFT JOIN",
+	}
+}
+
+func (s Subquery) RightJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  s,
+		right: right,
+		typ:   "RIGHT JOIN",
+	}
+}
+
+func (s Subquery) CrossJoin(right TableReference) Join {
+	return Join{
+		left:  s,
+		right: right,
+		typ:   "CROSS JOIN",
+	}
+}
+
+func (s Subquery) FullOuterJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  s,
+		right: right,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
+func (s Subquery) expr() {}
+
+func (s Subquery) table() {}
+
+// Exists 子查询有结果的时候为真
+func Exists(sub Subquery) Predicate {
+	return Predicate{
+		op:    opExists,
+		right: sub,
+	}
+}
+
+// NotExists 子查询没有结果的时候为真
+func NotExists(sub Subquery) Predicate {
+	return Predicate{
+		op:    opNotExists,
+		right: sub,
+	}
+}
@@ -0,0 +1,160 @@
+package gsql
+
+import (
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelector_Subquery(t *testing.T) {
+	db := memoryDB(t)
+	type Order struct {
+		Id     int
+		UserId int
+		Amount int
+	}
+
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "from subquery",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Where(C("Amount").Gt(100)).AsSubquery("sub")
+				return NewSelector[Order](db).From(sub).Where(sub.C("UserId").Eq(1))
+			}(),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM (SELECT * FROM `order` WHERE `amount` > ?) AS `sub` WHERE `sub`.`user_id` = ?;",
+				Args: []any{100, 1},
+			},
+		},
+		{
+			name: "subquery columns",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Select(C("UserId").As("uid"), Sum("Amount").As("total")).
+					GroupBy(C("UserId")).AsSubquery("sub")
+				return NewSelector[Order](db).Select(sub.C("uid"), sub.C("total").As("amount")).
+					From(sub).Where(sub.C("total").Gt(1000))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT `sub`.`uid`,`sub`.`total` AS `amount` FROM " +
+					"(SELECT `user_id` AS `uid`,SUM(`amount`) AS `total` FROM `order` GROUP BY `user_id`) AS `sub` " +
+					"WHERE `sub`.`total` > ?;",
+				Args: []any{1000},
+			},
+		},
+		{
+			name: "subquery field name",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[Order](db).Select(sub.C("UserId")).From(sub)
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT `sub`.`user_id` FROM (SELECT `user_id` FROM `order`) AS `sub`;",
+			},
+		},
+		{
+			name: "subquery column not selected",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[Order](db).Select(sub.C("Amount")).From(sub)
+			}(),
+			wantErr: errs.NewErrUnknownField("Amount"),
+		},
+		{
+			name: "join subquery",
+			s: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				sub := NewSelector[Order](db).Where(C("Amount").Gt(100)).AsSubquery("sub")
+				return NewSelector[TestModel](db).Select(t1.C("FirstName"), sub.C("Amount")).
+					From(t1.Join(sub).On(t1.C("Id").Eq(sub.C("UserId")))).
+					Where(t1.C("Age").Gt(18))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT `t1`.`first_name`,`sub`.`amount` FROM " +
+					"(`test_model` AS `t1` JOIN (SELECT * FROM `order` WHERE `amount` > ?) AS `sub` " +
+					"ON `t1`.`id` = `sub`.`user_id`) WHERE `t1`.`age` > ?;",
+				Args: []any{100, 18},
+			},
+		},
+		{
+			name: "in subquery",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Select(C("UserId")).Where(C("Amount").Gt(100)).AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(C("Age").Gt(18), C("Id").In(sub))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE (`age` > ?) AND " +
+					"(`id` IN (SELECT `user_id` FROM `order` WHERE `amount` > ?));",
+				Args: []any{18, 100},
+			},
+		},
+		{
+			name: "not in subquery",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Select(C("UserId")).AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(C("Id").NotIn(sub))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` WHERE `id` NOT IN (SELECT `user_id` FROM `order`);",
+			},
+		},
+		{
+			name: "correlated exists",
+			s: func() QueryBuilder {
+				t1 := TableOf(&TestModel{}).As("t1")
+				t2 := TableOf(&Order{}).As("t2")
+				sub := NewSelector[Order](db).Select(Raw("1")).From(t2).
+					Where(t2.C("UserId").Eq(t1.C("Id")), t2.C("Amount").Gt(100)).AsSubquery("")
+				return NewSelector[TestModel](db).From(t1).Where(t1.C("Age").Gt(18), Exists(sub))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT * FROM `test_model` AS `t1` WHERE (`t1`.`age` > ?) AND " +
+					"( EXISTS (SELECT 1 FROM `order` AS `t2` WHERE (`t2`.`user_id` = `t1`.`id`) AND (`t2`.`amount` > ?)));",
+				Args: []any{18, 100},
+			},
+		},
+		{
+			name: "not exists",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Where(C("UserId").Eq(1)).AsSubquery("")
+				return NewSelector[TestModel](db).Where(NotExists(sub))
+			}(),
+			wantQuery: &Query{
+				SQL:  "SELECT * FROM `test_model` WHERE  NOT EXISTS (SELECT * FROM `order` WHERE `user_id` = ?);",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "invalid subquery",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Where(C("Invalid").Eq(1)).AsSubquery("sub")
+				return NewSelector[TestModel](db).Where(C("Id").In(sub))
+			}(),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_BuildTwice(t *testing.T) {
+	s := NewSelector[TestModel](memoryDB(t)).Where(C("Id").Eq(1))
+	q1, err := s.Build()
+	assert.NoError(t, err)
+	q2, err := s.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, q1, q2)
+}
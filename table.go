@@ -7,6 +7,7 @@ type TableReference interface {
 type Table struct {
 	entity any
 	alias  string
+	hints  []indexHint
 }
 
 func TableOf(entity any) Table {
@@ -19,6 +20,7 @@ func (t Table) As(alias string) Table {
 	return Table{
 		entity: t.entity,
 		alias:  alias,
+		hints:  t.hints,
 	}
 }
 
@@ -55,6 +57,24 @@ func (t Table) RightJoin(right TableReference) *JoinBuilder {
 	}
 }
 
+// CrossJoin 笛卡尔积，不需要 ON 或者 USING
+func (t Table) CrossJoin(right TableReference) Join {
+	return Join{
+		left:  t,
+		right: right,
+		typ:   "CROSS JOIN",
+	}
+}
+
+// FullOuterJoin 全外连接，MySQL 不支持，会使用 LEFT JOIN 和 RIGHT JOIN 的 UNION 模拟
+func (t Table) FullOuterJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  t,
+		right: right,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
 type Join struct {
 	left  TableReference
 	right TableReference
@@ -63,9 +83,14 @@ type Join struct {
 	using []string
 }
 
-func (j Join) table() {
-	//TODO implement me
-	panic("implement me")
+func (j Join) table() {}
+
+// C 引用 JOIN 里面某张表的列，按照从左到右的顺序找到第一个有这个字段的表
+func (j Join) C(name string) Column {
+	return Column{
+		Name:  name,
+		Table: j,
+	}
 }
 
 func (j Join) Join(right TableReference) *JoinBuilder {
@@ -92,6 +117,22 @@ func (j Join) RightJoin(right TableReference) *JoinBuilder {
 	}
 }
 
+func (j Join) CrossJoin(right TableReference) Join {
+	return Join{
+		left:  j,
+		right: right,
+		typ:   "CROSS JOIN",
+	}
+}
+
+func (j Join) FullOuterJoin(right TableReference) *JoinBuilder {
+	return &JoinBuilder{
+		left:  j,
+		right: right,
+		typ:   "FULL OUTER JOIN",
+	}
+}
+
 type JoinBuilder struct {
 	left  TableReference
 	right TableReference
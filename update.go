@@ -0,0 +1,133 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"strings"
+)
+
+type Updater[T any] struct {
+	builder
+	assigns []Assignable
+	val     *T
+	where   []Predicate
+
+	session Session
+}
+
+func NewUpdater[T any](db Session) *Updater[T] {
+	base := db.getCore()
+	m, err := base.r.Register(new(T))
+	if err != nil {
+		panic(err)
+	}
+
+	return &Updater[T]{
+		builder: builder{
+			core: core{
+				model:   m,
+				dialect: base.dialect,
+				creator: base.creator,
+				r:       base.r,
+				mdls:    base.mdls,
+			},
+			sb:     strings.Builder{},
+			quoter: base.dialect.quoter(),
+		},
+		session: db,
+	}
+}
+
+func (u *Updater[T]) Build() (*Query, error) {
+	if len(u.assigns) == 0 {
+		return nil, errs.ErrNoUpdatedColumns
+	}
+	u.reset()
+
+	u.sb.WriteString("UPDATE ")
+	u.quote(u.model.TableName)
+	u.sb.WriteString(" SET ")
+
+	for idx, assign := range u.assigns {
+		if idx > 0 {
+			u.sb.WriteByte(',')
+		}
+		switch a := assign.(type) {
+		case Assignment:
+			fd, ok := u.model.FieldMap[a.col]
+			if !ok {
+				return nil, errs.NewErrUnknownField(a.col)
+			}
+			u.quote(fd.ColName)
+			u.sb.WriteByte('=')
+			if err := u.buildExpression(a.val); err != nil {
+				return nil, err
+			}
+		case Column:
+			// 直接使用列的时候，值从 Update 传入的实体里面取
+			if u.val == nil {
+				return nil, errs.ErrNoUpdatedEntity
+			}
+			fd, ok := u.model.FieldMap[a.Name]
+			if !ok {
+				return nil, errs.NewErrUnknownField(a.Name)
+			}
+			val, err := u.creator(u.model, u.val).Field(a.Name)
+			if err != nil {
+				return nil, err
+			}
+			u.quote(fd.ColName)
+			u.sb.WriteString("=?")
+			u.addArgs(val)
+		default:
+			return nil, errs.NewErrUnsupportedAssignable(assign)
+		}
+	}
+
+	if len(u.where) > 0 {
+		u.sb.WriteString(" WHERE ")
+		if err := u.buildPredicates(u.where); err != nil {
+			return nil, err
+		}
+	}
+
+	u.sb.WriteByte(';')
+
+	return &Query{
+		SQL:  u.sb.String(),
+		Args: u.args,
+	}, nil
+}
+
+func (u *Updater[T]) Exec(ctx context.Context) Result {
+	res := exec(ctx, u.session, u.core, &QueryContext{
+		Type:    TypeUpdate,
+		Builder: u,
+		Model:   u.model,
+	})
+
+	// execHandler 返回的 Result 里面已经带上了错误
+	if r, ok := res.Result.(Result); ok {
+		return r
+	}
+
+	return Result{
+		err: res.Err,
+	}
+}
+
+// Update 指定更新使用的实体，Set 里面的 Column 会从这个实体里面取值
+func (u *Updater[T]) Update(val *T) *Updater[T] {
+	u.val = val
+	return u
+}
+
+func (u *Updater[T]) Set(assigns ...Assignable) *Updater[T] {
+	u.assigns = assigns
+	return u
+}
+
+func (u *Updater[T]) Where(ps ...Predicate) *Updater[T] {
+	u.where = ps
+	return u
+}
@@ -0,0 +1,174 @@
+package gsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestUpdater_Build(t *testing.T) {
+	db := memoryDB(t)
+	testCases := []struct {
+		name string
+		u    QueryBuilder
+
+		wantRes *Query
+		wantErr error
+	}{
+		{
+			name:    "no columns",
+			u:       NewUpdater[TestModel](db),
+			wantErr: errs.ErrNoUpdatedColumns,
+		},
+		{
+			name: "assign value",
+			u:    NewUpdater[TestModel](db).Set(Assign("Age", 18)),
+			wantRes: &Query{
+				SQL:  "UPDATE `test_model` SET `age`=?;",
+				Args: []any{18},
+			},
+		},
+		{
+			name: "entity columns",
+			u: NewUpdater[TestModel](db).Update(&TestModel{
+				Id:        12,
+				FirstName: "Tom",
+				Age:       18,
+				LastName:  &sql.NullString{String: "Jerry", Valid: true},
+			}).Set(C("FirstName"), C("Age")),
+			wantRes: &Query{
+				SQL:  "UPDATE `test_model` SET `first_name`=?,`age`=?;",
+				Args: []any{"Tom", int8(18)},
+			},
+		},
+		{
+			name: "mixed and where",
+			u: NewUpdater[TestModel](db).Update(&TestModel{
+				FirstName: "Tom",
+			}).Set(C("FirstName"), Assign("Age", 19)).
+				Where(C("Id").Eq(12), C("Age").Eq(18)),
+			wantRes: &Query{
+				SQL:  "UPDATE `test_model` SET `first_name`=?,`age`=? WHERE (`id` = ?) AND (`age` = ?);",
+				Args: []any{"Tom", 19, 12, 18},
+			},
+		},
+		{
+			name: "math expression",
+			u: NewUpdater[TestModel](db).Set(Assign("Age", C("Age").Sub(1))).
+				Where(C("Age").Ge(1)),
+			wantRes: &Query{
+				SQL:  "UPDATE `test_model` SET `age`=`age` - ? WHERE `age` >= ?;",
+				Args: []any{1, 1},
+			},
+		},
+		{
+			name: "nested math expression",
+			u:    NewUpdater[TestModel](db).Set(Assign("Age", C("Age").Add(1).Multi(2))),
+			wantRes: &Query{
+				SQL:  "UPDATE `test_model` SET `age`=(`age` + ?) * ?;",
+				Args: []any{1, 2},
+			},
+		},
+		{
+			name:    "column without entity",
+			u:       NewUpdater[TestModel](db).Set(C("FirstName")),
+			wantErr: errs.ErrNoUpdatedEntity,
+		},
+		{
+			name:    "invalid assign",
+			u:       NewUpdater[TestModel](db).Set(Assign("Invalid", 18)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name:    "invalid column",
+			u:       NewUpdater[TestModel](db).Update(&TestModel{}).Set(C("Invalid")),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name:    "invalid where",
+			u:       NewUpdater[TestModel](db).Set(Assign("Age", 18)).Where(C("Invalid").Eq(1)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.u.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantRes, q)
+		})
+	}
+}
+
+func TestUpdater_SQLite(t *testing.T) {
+	db := memoryDB(t, WithDialect(DialectSQLite))
+	q, err := NewUpdater[TestModel](db).Set(Assign("FirstName", "Tom")).
+		Where(C("Id").Eq(1)).Build()
+	require.NoError(t, err)
+	assert.Equal(t, &Query{
+		SQL:  "UPDATE `test_model` SET `first_name`=? WHERE `id` = ?;",
+		Args: []any{"Tom", 1},
+	}, q)
+}
+
+func TestUpdater_Exec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+	testCases := []struct {
+		name     string
+		u        *Updater[TestModel]
+		wantErr  error
+		affected int64
+	}{
+		{
+			name:    "query error",
+			u:       NewUpdater[TestModel](db).Set(Assign("Invalid", 1)),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+		{
+			name:    "no columns",
+			u:       NewUpdater[TestModel](db),
+			wantErr: errs.ErrNoUpdatedColumns,
+		},
+		{
+			name: "session error",
+			u: func() *Updater[TestModel] {
+				mock.ExpectExec("UPDATE .*").
+					WillReturnError(errors.New("session error"))
+				return NewUpdater[TestModel](db).Set(Assign("Age", 18))
+			}(),
+			wantErr: errors.New("session error"),
+		},
+		{
+			name: "exec",
+			u: func() *Updater[TestModel] {
+				mock.ExpectExec("UPDATE .*").
+					WillReturnResult(driver.RowsAffected(1))
+				return NewUpdater[TestModel](db).Set(Assign("Age", 18)).Where(C("Id").Eq(1))
+			}(),
+			affected: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := tc.u.Exec(context.Background())
+			assert.Equal(t, tc.wantErr, res.Err())
+			affected, err := res.RowsAffected()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.affected, affected)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package gsql
+
+import "strconv"
+
+// Window 窗口定义，也就是 OVER 后面括号里面的内容
+type Window struct {
+	partitionBy []Expression
+	orderBy     []OrderBy
+	frame       *windowFrame
+}
+
+type windowFrame struct {
+	// typ 是 ROWS 或者 RANGE
+	typ   string
+	start FrameBound
+	end   FrameBound
+}
+
+// FrameBound 窗口帧的边界
+type FrameBound struct {
+	bound string
+	// offset 只在 PRECEDING 和 FOLLOWING 的时候使用
+	offset int
+}
+
+var (
+	UnboundedPreceding = FrameBound{bound: "UNBOUNDED PRECEDING"}
+	CurrentRow         = FrameBound{bound: "CURRENT ROW"}
+	UnboundedFollowing = FrameBound{bound: "UNBOUNDED FOLLOWING"}
+)
+
+// Preceding 当前行之前的第 n 行
+func Preceding(n int) FrameBound {
+	return FrameBound{
+		bound:  "PRECEDING",
+		offset: n,
+	}
+}
+
+// Following 当前行之后的第 n 行
+func Following(n int) FrameBound {
+	return FrameBound{
+		bound:  "FOLLOWING",
+		offset: n,
+	}
+}
+
+func NewWindow() Window {
+	return Window{}
+}
+
+func (w Window) PartitionBy(exprs ...Expression) Window {
+	w.partitionBy = exprs
+	return w
+}
+
+func (w Window) OrderBy(obs ...OrderBy) Window {
+	w.orderBy = obs
+	return w
+}
+
+// Rows ROWS BETWEEN start AND end
+func (w Window) Rows(start FrameBound, end FrameBound) Window {
+	w.frame = &windowFrame{
+		typ:   "ROWS",
+		start: start,
+		end:   end,
+	}
+	return w
+}
+
+// Range RANGE BETWEEN start AND end
+func (w Window) Range(start FrameBound, end FrameBound) Window {
+	w.frame = &windowFrame{
+		typ:   "RANGE",
+		start: start,
+		end:   end,
+	}
+	return w
+}
+
+// WindowFunc 窗口函数，例如 ROW_NUMBER() OVER (PARTITION BY ... ORDER BY ...)
+type WindowFunc struct {
+	// fn 是窗口函数本身，可能是 windowCall 或者 Aggregate
+	fn     Expression
+	window Window
+	alias  string
+}
+
+// windowCall 专用的窗口函数调用，例如 ROW_NUMBER()
+type windowCall struct {
+	name string
+	args []Expression
+}
+
+func (windowCall) expr() {}
+
+// offsetLiteral LAG 和 LEAD 的偏移量，
+// 部分数据库不支持在这里使用参数，所以直接拼接在 SQL 里面
+type offsetLiteral int
+
+func (offsetLiteral) expr() {}
+
+func RowNumber() WindowFunc {
+	return WindowFunc{
+		fn: windowCall{name: "ROW_NUMBER"},
+	}
+}
+
+func Rank() WindowFunc {
+	return WindowFunc{
+		fn: windowCall{name: "RANK"},
+	}
+}
+
+func DenseRank() WindowFunc {
+	return WindowFunc{
+		fn: windowCall{name: "DENSE_RANK"},
+	}
+}
+
+// Lag 取当前行之前第 offset 行的 expr，def 是可选的默认值
+func Lag(expr Expression, offset int, def ...any) WindowFunc {
+	return WindowFunc{
+		fn: offsetCall("LAG", expr, offset, def),
+	}
+}
+
+// Lead 取当前行之后第 offset 行的 expr，def 是可选的默认值
+func Lead(expr Expression, offset int, def ...any) WindowFunc {
+	return WindowFunc{
+		fn: offsetCall("LEAD", expr, offset, def),
+	}
+}
+
+func offsetCall(name string, expr Expression, offset int, def []any) windowCall {
+	args := []Expression{expr, offsetLiteral(offset)}
+	if len(def) > 0 {
+		args = append(args, valueOf(def[0]))
+	}
+	return windowCall{
+		name: name,
+		args: args,
+	}
+}
+
+// Over 聚合函数作为窗口函数使用，例如 SUM(`amount`) OVER (...)
+func (a Aggregate) Over(w Window) WindowFunc {
+	a.alias = ""
+	return WindowFunc{
+		fn:     a,
+		window: w,
+	}
+}
+
+func (w WindowFunc) Over(win Window) WindowFunc {
+	w.window = win
+	return w
+}
+
+func (w WindowFunc) As(alias string) WindowFunc {
+	w.alias = alias
+	return w
+}
+
+func (w WindowFunc) expr() {}
+
+func (w WindowFunc) selectable() {}
+
+func (b *builder) buildWindowFunc(w WindowFunc) error {
+	switch fn := w.fn.(type) {
+	case windowCall:
+		b.sb.WriteString(fn.name)
+		b.sb.WriteByte('(')
+		for i, arg := range fn.args {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			if offset, ok := arg.(offsetLiteral); ok {
+				b.sb.WriteString(strconv.Itoa(int(offset)))
+				continue
+			}
+			if err := b.buildExpression(arg); err != nil {
+				return err
+			}
+		}
+		b.sb.WriteByte(')')
+	default:
+		if err := b.buildExpression(fn); err != nil {
+			return err
+		}
+	}
+
+	b.sb.WriteString(" OVER (")
+	if err := b.buildWindow(w.window); err != nil {
+		return err
+	}
+	b.sb.WriteByte(')')
+
+	if w.alias != "" {
+		b.sb.WriteString(" AS ")
+		b.quote(w.alias)
+	}
+	return nil
+}
+
+func (b *builder) buildWindow(w Window) error {
+	if len(w.partitionBy) > 0 {
+		b.sb.WriteString("PARTITION BY ")
+		for i, expr := range w.partitionBy {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			if err := b.buildExpression(expr); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(w.orderBy) > 0 {
+		if len(w.partitionBy) > 0 {
+			b.sb.WriteByte(' ')
+		}
+		b.sb.WriteString("ORDER BY ")
+		for i, ob := range w.orderBy {
+			if i > 0 {
+				b.sb.WriteByte(',')
+			}
+			if err := b.dialect.buildOrderBy(b, ob); err != nil {
+				return err
+			}
+		}
+	}
+
+	if w.frame != nil {
+		if len(w.partitionBy) > 0 || len(w.orderBy) > 0 {
+			b.sb.WriteByte(' ')
+		}
+		b.sb.WriteString(w.frame.typ)
+		b.sb.WriteString(" BETWEEN ")
+		b.buildFrameBound(w.frame.start)
+		b.sb.WriteString(" AND ")
+		b.buildFrameBound(w.frame.end)
+	}
+	return nil
+}
+
+func (b *builder) buildFrameBound(fb FrameBound) {
+	if fb.bound == "PRECEDING" || fb.bound == "FOLLOWING" {
+		b.sb.WriteString(strconv.Itoa(fb.offset))
+		b.sb.WriteByte(' ')
+	}
+	b.sb.WriteString(fb.bound)
+}
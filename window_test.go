@@ -0,0 +1,141 @@
+package gsql
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DaHuangQwQ/gsql/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSelector_Window(t *testing.T) {
+	db := memoryDB(t)
+	type Order struct {
+		Id     int64
+		UserId int64
+		Amount int64
+	}
+
+	testCases := []struct {
+		name      string
+		s         QueryBuilder
+		wantQuery *Query
+		wantErr   error
+	}{
+		{
+			name: "row number",
+			s: NewSelector[Order](db).Select(C("Id"),
+				RowNumber().Over(NewWindow().PartitionBy(C("UserId")).OrderBy(Desc(C("Id")))).As("rn")),
+			wantQuery: &Query{
+				SQL: "SELECT `id`,ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `id` DESC) AS `rn` FROM `order`;",
+			},
+		},
+		{
+			name: "rank dense rank",
+			s: NewSelector[Order](db).Select(
+				Rank().Over(NewWindow().OrderBy(Desc(C("Amount")))),
+				DenseRank().Over(NewWindow().OrderBy(Desc(C("Amount")))).As("dr")),
+			wantQuery: &Query{
+				SQL: "SELECT RANK() OVER (ORDER BY `amount` DESC),DENSE_RANK() OVER (ORDER BY `amount` DESC) AS `dr` FROM `order`;",
+			},
+		},
+		{
+			name: "lag lead",
+			s: NewSelector[Order](db).Select(
+				Lag(C("Amount"), 1).Over(NewWindow().PartitionBy(C("UserId")).OrderBy(Asc(C("Id")))).As("prev"),
+				Lead(C("Amount"), 2, 0).Over(NewWindow().OrderBy(Asc(C("Id")))).As("next")),
+			wantQuery: &Query{
+				SQL: "SELECT LAG(`amount`,1) OVER (PARTITION BY `user_id` ORDER BY `id` ASC) AS `prev`," +
+					"LEAD(`amount`,2,?) OVER (ORDER BY `id` ASC) AS `next` FROM `order`;",
+				Args: []any{0},
+			},
+		},
+		{
+			name: "aggregate over frame",
+			s: NewSelector[Order](db).Select(C("Id"),
+				Sum("Amount").Over(NewWindow().PartitionBy(C("UserId")).OrderBy(Asc(C("Id"))).
+					Rows(UnboundedPreceding, CurrentRow)).As("running_total"),
+				Avg("Amount").Over(NewWindow().OrderBy(Asc(C("Id"))).Rows(Preceding(2), Following(1))),
+				Count("Id").Over(NewWindow().Range(UnboundedPreceding, UnboundedFollowing))),
+			wantQuery: &Query{
+				SQL: "SELECT `id`," +
+					"SUM(`amount`) OVER (PARTITION BY `user_id` ORDER BY `id` ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS `running_total`," +
+					"AVG(`amount`) OVER (ORDER BY `id` ASC ROWS BETWEEN 2 PRECEDING AND 1 FOLLOWING)," +
+					"COUNT(`id`) OVER (RANGE BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) FROM `order`;",
+			},
+		},
+		{
+			name: "empty window",
+			s:    NewSelector[Order](db).Select(Count("Id").Over(NewWindow()).As("total")),
+			wantQuery: &Query{
+				SQL: "SELECT COUNT(`id`) OVER () AS `total` FROM `order`;",
+			},
+		},
+		{
+			name: "latest row per group",
+			s: func() QueryBuilder {
+				sub := NewSelector[Order](db).Select(C("Id"), C("UserId"), C("Amount"),
+					RowNumber().Over(NewWindow().PartitionBy(C("UserId")).OrderBy(Desc(C("Id")))).As("rn")).
+					AsSubquery("t")
+				return NewSelector[Order](db).Select(sub.C("Id"), sub.C("UserId"), sub.C("Amount")).
+					From(sub).Where(sub.C("rn").Eq(1))
+			}(),
+			wantQuery: &Query{
+				SQL: "SELECT `t`.`id`,`t`.`user_id`,`t`.`amount` FROM " +
+					"(SELECT `id`,`user_id`,`amount`,ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `id` DESC) AS `rn` FROM `order`) AS `t` " +
+					"WHERE `t`.`rn` = ?;",
+				Args: []any{1},
+			},
+		},
+		{
+			name: "order by window alias",
+			s: NewSelector[Order](db).Select(C("Id"),
+				Rank().Over(NewWindow().OrderBy(Desc(C("Amount")))).As("rk")).
+				OrderBy(Asc(C("rk"))),
+			wantQuery: &Query{
+				SQL: "SELECT `id`,RANK() OVER (ORDER BY `amount` DESC) AS `rk` FROM `order` ORDER BY `rk` ASC;",
+			},
+		},
+		{
+			name: "invalid partition column",
+			s: NewSelector[Order](db).Select(
+				RowNumber().Over(NewWindow().PartitionBy(C("Invalid")))),
+			wantErr: errs.NewErrUnknownField("Invalid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := tc.s.Build()
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantQuery, q)
+		})
+	}
+}
+
+func TestSelector_Window_Scan(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	db, err := OpenDB(mockDB)
+	require.NoError(t, err)
+
+	type RankedOrder struct {
+		Id   int64
+		Rank int64
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "rank"})
+	rows.AddRow(3, 1)
+	rows.AddRow(1, 2)
+	mock.ExpectQuery("SELECT `id`,RANK\\(\\) OVER .* AS `rank` FROM `ranked_order`;").WillReturnRows(rows)
+
+	res, err := NewSelector[RankedOrder](db).Select(C("Id"),
+		Rank().Over(NewWindow().OrderBy(Desc(C("Id")))).As("rank")).
+		GetMulti(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*RankedOrder{{Id: 3, Rank: 1}, {Id: 1, Rank: 2}}, res)
+}